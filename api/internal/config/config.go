@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/zeromicro/go-zero/core/stores/redis"
 	"github.com/zeromicro/go-zero/rest"
 )
@@ -8,9 +10,27 @@ import (
 type Config struct {
 	rest.RestConf
 
-	MySQL MySQLConfig
-	Redis redis.RedisConf
-	Auth  AuthConfig
+	MySQL     MySQLConfig
+	Redis     redis.RedisConf
+	Auth      AuthConfig
+	Job       JobConfig
+	Workflow  WorkflowConfig
+	Version   VersionConfig
+	Draft     DraftConfig
+	Summary   SummaryConfig
+	Trash     TrashConfig
+	Batch     BatchConfig
+	Comment   CommentConfig
+	View      ViewConfig
+	Analytics AnalyticsConfig
+	Ranking   RankingConfig
+	Feed      FeedConfig
+
+	Site        SiteConfig
+	Syndication SyndicationConfig
+	Sitemap     SitemapConfig
+
+	Media MediaConfig
 }
 
 type MySQLConfig struct {
@@ -23,3 +43,156 @@ type AuthConfig struct {
 	AccessSecret string
 	AccessExpire int64
 }
+
+// JobConfig 后台任务配置
+type JobConfig struct {
+	Enabled          bool          `json:",default=true"`
+	ScheduleInterval time.Duration `json:",default=30s"` // 定时发布检查间隔
+	CompactInterval  time.Duration `json:",default=1h"`  // 版本历史压缩间隔
+	CounterInterval  time.Duration `json:",default=10m"` // 点赞数、关注数校正间隔
+	PruneInterval    time.Duration `json:",default=1h"`  // 清理过期访客去重记录的间隔
+	RankingInterval  time.Duration `json:",default=10m"` // 热度排行裁剪间隔
+	MediaInterval    time.Duration `json:",default=1m"`  // 重新排队未处理图片的间隔
+	TrashInterval    time.Duration `json:",default=1h"`  // 清理回收站过期文章的间隔
+}
+
+// WorkflowConfig 审核流程配置
+type WorkflowConfig struct {
+	RequireReview bool `json:",default=false"` // 开启后普通作者必须审核通过才能发布
+}
+
+// VersionConfig 版本历史存储配置
+type VersionConfig struct {
+	SnapshotInterval int `json:",default=10"` // 每隔多少个版本存一次完整快照
+	KeepAllDays      int `json:",default=30"` // 该天数内的版本全部保留，更早的每天只保留最后一个，0 表示不压缩
+}
+
+// DraftConfig 草稿自动保存配置
+type DraftConfig struct {
+	SnapshotLimit    int           `json:",default=20"`  // 每份草稿保留的快照数量
+	SnapshotInterval time.Duration `json:",default=1m"`  // 两次快照的最小间隔，避免每次自动保存都记录
+	ShrinkRatio      float64       `json:",default=0.8"` // 内容一次减少超过该比例时强制保留之前的快照
+	PinnedLimit      int           `json:",default=5"`   // 内容骤减前的快照单独保留的数量
+}
+
+// SummaryConfig 自动摘要配置，未填写摘要时从正文生成
+type SummaryConfig struct {
+	Length       int `json:",default=120,range=[10:500]"` // 摘要的最大字符数
+	MinParagraph int `json:",default=20"`                 // 短于该字符数的段落（如目录、问候语）不作为摘要
+}
+
+// TrashConfig 回收站配置，删除的文章在保留期内可以恢复
+type TrashConfig struct {
+	Retention time.Duration `json:",default=720h"` // 保留时长，到期后连同版本、草稿等永久删除
+	BatchSize int           `json:",default=100"`  // 定时任务每批永久删除的文章数
+}
+
+// BatchConfig 文章批量操作配置
+type BatchConfig struct {
+	MaxItems int `json:",default=500"` // 单次最多操作的文章数，按筛选条件匹配超过该数量时拒绝执行
+}
+
+// CommentConfig 评论配置
+type CommentConfig struct {
+	RequireApproval bool          `json:",default=false"` // 开启后评论需作者或管理员审核通过才公开
+	EditWindow      time.Duration `json:",default=15m"`   // 发表后可编辑的时长
+	DeleteWindow    time.Duration `json:",default=24h"`   // 发表后本人可删除的时长，作者和管理员不受限
+	MaxLength       int           `json:",default=5000"`  // 评论最大字符数
+	ReplyPreview    int           `json:",default=3"`     // 楼层列表中每层预览的回复数
+}
+
+// ViewConfig 浏览量统计配置
+type ViewConfig struct {
+	DedupWindow   time.Duration `json:",default=30m"` // 同一访客在该时间内重复浏览同一文章只计一次
+	FlushInterval time.Duration `json:",default=10s"` // 缓冲的浏览量写入数据库的间隔
+}
+
+// AnalyticsConfig 文章访问统计配置
+type AnalyticsConfig struct {
+	Timezone      string        `json:",default=Asia/Shanghai"` // 按该时区划分统计日期
+	FlushInterval time.Duration `json:",default=30s"`           // 缓冲的统计写入数据库的间隔
+	MaxRangeDays  int           `json:",default=366"`           // 单次查询的最大天数
+}
+
+// RankingConfig 文章排行配置
+type RankingConfig struct {
+	Redis         bool          `json:",default=false"` // 使用 Redis 有序集合增量维护热度，关闭时由数据库计算
+	HalfLife      time.Duration `json:",default=24h"`   // 热度半衰期
+	Window        int           `json:",default=7"`     // 数据库计算热度和最多浏览的统计天数
+	ViewWeight    float64       `json:",default=1"`
+	LikeWeight    float64       `json:",default=5"`
+	CommentWeight float64       `json:",default=10"`
+	Size          int           `json:",default=500"` // 热度排行保留的文章数
+	CacheTTL      time.Duration `json:",default=1m"`  // 数据库计算结果的缓存时间
+}
+
+// FeedConfig 关注动态配置
+type FeedConfig struct {
+	PageSize    int           `json:",default=20"`    // 默认每页条数
+	MaxPageSize int           `json:",default=50"`    // 每页最大条数
+	FolloweeTTL time.Duration `json:",default=5m"`    // 关注列表的缓存时间，关注变化时立即失效
+	PageTTL     time.Duration `json:",default=30s"`   // 动态分页结果的缓存时间
+	CacheLimit  int           `json:",default=10000"` // 每种缓存最多保留的条目数
+}
+
+// SiteConfig 站点信息，订阅源等需要完整链接的地方使用
+type SiteConfig struct {
+	Name        string `json:",default=acupofcoffee"`
+	Description string `json:",optional"`
+	URL         string `json:",default=http://localhost:8080"` // 站点对外的地址，文章链接为 URL/articles/<slug>
+	Language    string `json:",default=zh-CN"`
+}
+
+// SyndicationConfig RSS/Atom/JSON Feed 订阅源配置
+type SyndicationConfig struct {
+	Limit       int           `json:",default=20"`   // 每个订阅源包含的文章数
+	FullContent bool          `json:",default=true"` // 输出完整正文，关闭时只输出摘要
+	MaxAge      time.Duration `json:",default=10m"`  // 客户端缓存时间
+}
+
+// SitemapConfig 站点地图配置
+type SitemapConfig struct {
+	PageSize int           `json:",default=1000"` // 每页按文章ID划分的区间大小，不超过 50000
+	MaxAge   time.Duration `json:",default=1h"`   // 客户端缓存时间
+}
+
+// MediaConfig 文件上传配置
+type MediaConfig struct {
+	Storage       string        `json:",default=local,options=local|s3"`
+	MaxSize       int64         `json:",default=10485760"` // 单个文件的最大字节数
+	AllowedTypes  []string      `json:",optional"`         // 允许的类型（按文件内容识别），为空时允许 JPEG、PNG、GIF、WebP
+	UploadTimeout time.Duration `json:",default=1m"`       // 上传接口的超时时间
+	Local         LocalStorageConfig
+	S3            S3StorageConfig
+	Image         ImageConfig
+}
+
+// ImageConfig 图片处理配置。上传的图片在后台按宽度生成多个尺寸，
+// 变体输出为 JPEG，带透明通道的图片输出为无损 WebP
+type ImageConfig struct {
+	Workers   int           `json:",default=2"`                // 同时处理的图片数
+	QueueSize int           `json:",default=256"`              // 等待处理的队列长度，队列满时由定时任务稍后补上
+	Timeout   time.Duration `json:",default=2m"`               // 单张图片的处理超时
+	Quality   int           `json:",default=82,range=[1:100]"` // JPEG 质量
+	MaxPixels int64         `json:",default=50000000"`         // 像素数超过该值的图片不处理，避免解码占用过多内存
+	Thumbnail int           `json:",default=320"`              // 各尺寸的最大宽度，原图不超过该宽度时不生成
+	Medium    int           `json:",default=768"`
+	Large     int           `json:",default=1536"`
+}
+
+// LocalStorageConfig 本地文件系统存储
+type LocalStorageConfig struct {
+	Root    string `json:",default=data/uploads"`
+	BaseURL string `json:",default=/uploads"` // 文件的访问地址前缀，默认由本服务提供下载
+}
+
+// S3StorageConfig S3 兼容的对象存储
+type S3StorageConfig struct {
+	Endpoint  string `json:",optional"`
+	Region    string `json:",default=us-east-1"`
+	Bucket    string `json:",optional"`
+	AccessKey string `json:",optional"`
+	SecretKey string `json:",optional"`
+	PathStyle bool   `json:",default=true"` // MinIO 等自建服务使用 endpoint/bucket/key 形式的地址
+	PublicURL string `json:",optional"`     // 对外访问地址前缀，如 CDN 域名，为空时使用存储服务的地址
+}
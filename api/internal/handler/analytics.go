@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+
+	"acupofcoffee/api/internal/logic"
+	"acupofcoffee/api/internal/svc"
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/response"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// ArticleBeaconHandler 阅读上报，前端可在页面隐藏时用 navigator.sendBeacon 发送 application/json
+func ArticleBeaconHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.ArticleBeaconRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewAnalyticsLogic(r.Context(), ctx)
+		if err := l.Beacon(&req, visitorFromRequest(r)); err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, nil)
+	}
+}
+
+func ArticleStatsHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.ArticleStatsRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewAnalyticsLogic(r.Context(), ctx)
+		resp, err := l.Stats(&req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, resp)
+	}
+}
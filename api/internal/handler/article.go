@@ -3,6 +3,7 @@ package handler
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"acupofcoffee/api/internal/logic"
 	"acupofcoffee/api/internal/svc"
@@ -50,6 +51,21 @@ func UpdateArticleHandler(ctx *svc.ServiceContext) http.HandlerFunc {
 	}
 }
 
+func TrendingArticlesHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := parseArticleListRequest(r)
+
+		l := logic.NewArticleLogic(r.Context(), ctx)
+		resp, err := l.Trending(req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, resp)
+	}
+}
+
 func GetArticleHandler(ctx *svc.ServiceContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req types.IDRequest
@@ -59,7 +75,7 @@ func GetArticleHandler(ctx *svc.ServiceContext) http.HandlerFunc {
 		}
 
 		l := logic.NewArticleLogic(r.Context(), ctx)
-		resp, err := l.Get(req.ID)
+		resp, err := l.Get(req.ID, visitorFromRequest(r))
 		if err != nil {
 			response.Error(w, err)
 			return
@@ -69,42 +85,42 @@ func GetArticleHandler(ctx *svc.ServiceContext) http.HandlerFunc {
 	}
 }
 
-func ListArticleHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+// GetArticleBySlugHandler 通过永久链接获取文章，旧链接 301 跳转到当前链接
+func GetArticleBySlugHandler(ctx *svc.ServiceContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		req := types.ArticleListRequest{
-			Page:     1,
-			PageSize: 10,
+		var req types.ArticleSlugRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
 		}
 
-		// 手动解析 query 参数
-		query := r.URL.Query()
-		if v := query.Get("page"); v != "" {
-			if page, err := strconv.Atoi(v); err == nil {
-				req.Page = page
-			}
-		}
-		if v := query.Get("pageSize"); v != "" {
-			if pageSize, err := strconv.Atoi(v); err == nil {
-				req.PageSize = pageSize
-			}
-		}
-		if v := query.Get("status"); v != "" {
-			if status, err := strconv.Atoi(v); err == nil {
-				s := int8(status)
-				req.Status = &s
-			}
+		l := logic.NewArticleLogic(r.Context(), ctx)
+		id, slug, err := l.ResolveSlug(req.Slug)
+		if err != nil {
+			response.Error(w, err)
+			return
 		}
-		if v := query.Get("keyword"); v != "" {
-			req.Keyword = v
+		if slug != req.Slug {
+			http.Redirect(w, r, "/api/v1/articles/by-slug/"+slug, http.StatusMovedPermanently)
+			return
 		}
-		if v := query.Get("authorId"); v != "" {
-			if authorId, err := strconv.Atoi(v); err == nil {
-				req.AuthorID = uint(authorId)
-			}
+
+		resp, err := l.Get(id, visitorFromRequest(r))
+		if err != nil {
+			response.Error(w, err)
+			return
 		}
 
+		response.Success(w, resp)
+	}
+}
+
+func ListArticleHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := parseArticleListRequest(r)
+
 		l := logic.NewArticleLogic(r.Context(), ctx)
-		resp, err := l.List(&req)
+		resp, err := l.List(req)
 		if err != nil {
 			response.Error(w, err)
 			return
@@ -114,6 +130,86 @@ func ListArticleHandler(ctx *svc.ServiceContext) http.HandlerFunc {
 	}
 }
 
+// parseArticleListRequest 手动解析列表的 query 参数
+func parseArticleListRequest(r *http.Request) *types.ArticleListRequest {
+	req := &types.ArticleListRequest{
+		Page:     1,
+		PageSize: 10,
+	}
+
+	query := r.URL.Query()
+	if v := query.Get("page"); v != "" {
+		if page, err := strconv.Atoi(v); err == nil {
+			req.Page = page
+		}
+	}
+	if v := query.Get("pageSize"); v != "" {
+		if pageSize, err := strconv.Atoi(v); err == nil {
+			req.PageSize = pageSize
+		}
+	}
+	// status 可以传多个：status=1,2 或 status=1&status=2
+	var statuses []int8
+	for _, v := range query["status"] {
+		for _, part := range strings.Split(v, ",") {
+			if status, err := strconv.Atoi(strings.TrimSpace(part)); err == nil {
+				statuses = append(statuses, int8(status))
+			}
+		}
+	}
+	if len(statuses) == 1 {
+		req.Status = &statuses[0]
+	} else if len(statuses) > 1 {
+		req.Statuses = statuses
+	}
+	if v := query.Get("keyword"); v != "" {
+		req.Keyword = v
+	}
+	if v := query.Get("authorId"); v != "" {
+		if authorId, err := strconv.Atoi(v); err == nil {
+			req.AuthorID = uint(authorId)
+		}
+	}
+	if v := query.Get("tagId"); v != "" {
+		if tagId, err := strconv.Atoi(v); err == nil {
+			req.TagID = uint(tagId)
+		}
+	}
+	if v := query.Get("tag"); v != "" {
+		req.Tag = v
+	}
+	if v := query.Get("categoryId"); v != "" {
+		if categoryId, err := strconv.Atoi(v); err == nil {
+			req.CategoryID = uint(categoryId)
+		}
+	}
+	if v := query.Get("sort"); v != "" {
+		req.Sort = v
+	}
+	if v := query.Get("days"); v != "" {
+		if days, err := strconv.Atoi(v); err == nil {
+			req.Days = days
+		}
+	}
+	req.CreatedFrom = query.Get("createdFrom")
+	req.CreatedTo = query.Get("createdTo")
+	req.PublishedFrom = query.Get("publishedFrom")
+	req.PublishedTo = query.Get("publishedTo")
+	if v := query.Get("hasCover"); v != "" {
+		if hasCover, err := strconv.ParseBool(v); err == nil {
+			req.HasCover = &hasCover
+		}
+	}
+	req.Cursor = query.Get("cursor")
+	if v := query.Get("total"); v != "" {
+		if withTotal, err := strconv.ParseBool(v); err == nil {
+			req.WithTotal = &withTotal
+		}
+	}
+
+	return req
+}
+
 func DeleteArticleHandler(ctx *svc.ServiceContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req types.IDRequest
@@ -193,3 +289,41 @@ func RestoreVersionHandler(ctx *svc.ServiceContext) http.HandlerFunc {
 		response.Success(w, resp)
 	}
 }
+
+func CompareVersionsHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.CompareVersionsRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewArticleLogic(r.Context(), ctx)
+		resp, err := l.CompareVersions(&req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, resp)
+	}
+}
+
+func BatchArticlesHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.ArticleBatchRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewBatchLogic(r.Context(), ctx)
+		resp, err := l.Run(&req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, resp)
+	}
+}
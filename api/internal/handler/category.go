@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"net/http"
+
+	"acupofcoffee/api/internal/logic"
+	"acupofcoffee/api/internal/svc"
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/response"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func ListCategoryHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logic.NewCategoryLogic(r.Context(), ctx)
+		resp, err := l.List()
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, resp)
+	}
+}
+
+func CreateCategoryHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.CreateCategoryRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewCategoryLogic(r.Context(), ctx)
+		resp, err := l.Create(&req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, resp)
+	}
+}
+
+func UpdateCategoryHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.UpdateCategoryRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewCategoryLogic(r.Context(), ctx)
+		resp, err := l.Update(&req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, resp)
+	}
+}
+
+func DeleteCategoryHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.IDRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewCategoryLogic(r.Context(), ctx)
+		if err := l.Delete(req.ID); err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, nil)
+	}
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"net/http"
+
+	"acupofcoffee/api/internal/logic"
+	"acupofcoffee/api/internal/svc"
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/response"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func ListCollaboratorsHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.IDRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewCollaboratorLogic(r.Context(), ctx)
+		resp, err := l.List(req.ID)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, resp)
+	}
+}
+
+func InviteCollaboratorHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.InviteCollaboratorRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewCollaboratorLogic(r.Context(), ctx)
+		resp, err := l.Invite(&req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, resp)
+	}
+}
+
+func UpdateCollaboratorHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.UpdateCollaboratorRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewCollaboratorLogic(r.Context(), ctx)
+		resp, err := l.UpdateRole(&req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, resp)
+	}
+}
+
+func RemoveCollaboratorHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.CollaboratorRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewCollaboratorLogic(r.Context(), ctx)
+		if err := l.Remove(&req); err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, nil)
+	}
+}
+
+func ListInvitationsHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logic.NewCollaboratorLogic(r.Context(), ctx)
+		resp, err := l.Invitations()
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, resp)
+	}
+}
+
+func AcceptInvitationHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.IDRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewCollaboratorLogic(r.Context(), ctx)
+		if err := l.Accept(req.ID); err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, nil)
+	}
+}
+
+func DeclineInvitationHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.IDRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewCollaboratorLogic(r.Context(), ctx)
+		if err := l.Decline(req.ID); err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, nil)
+	}
+}
@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"net/http"
+
+	"acupofcoffee/api/internal/logic"
+	"acupofcoffee/api/internal/svc"
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/response"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func ListCommentsHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.CommentListRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewCommentLogic(r.Context(), ctx)
+		resp, err := l.List(&req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, resp)
+	}
+}
+
+func ListCommentRepliesHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.CommentRepliesRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewCommentLogic(r.Context(), ctx)
+		resp, err := l.ListReplies(&req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, resp)
+	}
+}
+
+func CreateCommentHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.CreateCommentRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewCommentLogic(r.Context(), ctx)
+		resp, err := l.Create(&req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, resp)
+	}
+}
+
+func UpdateCommentHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.UpdateCommentRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewCommentLogic(r.Context(), ctx)
+		resp, err := l.Update(&req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, resp)
+	}
+}
+
+func DeleteCommentHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.IDRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewCommentLogic(r.Context(), ctx)
+		if err := l.Delete(req.ID); err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, nil)
+	}
+}
+
+func PinCommentHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.IDRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewCommentLogic(r.Context(), ctx)
+		resp, err := l.Pin(req.ID)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, resp)
+	}
+}
+
+func UnpinCommentHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.IDRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewCommentLogic(r.Context(), ctx)
+		resp, err := l.Unpin(req.ID)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, resp)
+	}
+}
+
+func SetCommentStatusHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.CommentStatusRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewCommentLogic(r.Context(), ctx)
+		resp, err := l.SetStatus(&req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, resp)
+	}
+}
+
+func ListNotificationsHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.NotificationListRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewNotificationLogic(r.Context(), ctx)
+		resp, err := l.List(&req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, resp)
+	}
+}
+
+func ReadNotificationHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.IDRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewNotificationLogic(r.Context(), ctx)
+		if err := l.MarkRead(req.ID); err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, nil)
+	}
+}
+
+func ReadAllNotificationsHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logic.NewNotificationLogic(r.Context(), ctx)
+		if err := l.MarkAllRead(); err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, nil)
+	}
+}
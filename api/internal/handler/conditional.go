@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// serveConditional 输出可缓存的内容，ETag 取内容的哈希，Last-Modified 取 modtime（为零时不输出），
+// 由 http.ServeContent 处理 If-None-Match 和 If-Modified-Since 条件请求
+func serveConditional(w http.ResponseWriter, r *http.Request, contentType string, body []byte,
+	modtime time.Time, maxAge time.Duration) {
+	sum := sha1.Sum(body)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(maxAge.Seconds())))
+	http.ServeContent(w, r, "", modtime, bytes.NewReader(body))
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"net/http"
+
+	"acupofcoffee/api/internal/logic"
+	"acupofcoffee/api/internal/svc"
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/response"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func ListDraftHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logic.NewDraftLogic(r.Context(), ctx)
+		resp, err := l.List()
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, resp)
+	}
+}
+
+func GetDraftHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.IDRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewDraftLogic(r.Context(), ctx)
+		resp, err := l.Get(req.ID)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, resp)
+	}
+}
+
+func DeleteDraftHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.IDRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewDraftLogic(r.Context(), ctx)
+		if err := l.Delete(req.ID); err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, nil)
+	}
+}
+
+func ListDraftSnapshotsHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.IDRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewDraftLogic(r.Context(), ctx)
+		resp, err := l.ListSnapshots(req.ID)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, resp)
+	}
+}
+
+func GetDraftSnapshotHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.DraftSnapshotRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewDraftLogic(r.Context(), ctx)
+		resp, err := l.GetSnapshot(&req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, resp)
+	}
+}
+
+func RestoreDraftSnapshotHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.DraftSnapshotRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewDraftLogic(r.Context(), ctx)
+		resp, err := l.RestoreSnapshot(&req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, resp)
+	}
+}
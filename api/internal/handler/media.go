@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"acupofcoffee/api/internal/logic"
+	"acupofcoffee/api/internal/svc"
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/errorx"
+	"acupofcoffee/common/response"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// multipartOverhead 请求体中除文件内容外的表单边界和字段
+const multipartOverhead = 1 << 20
+
+// UploadMediaHandler 上传文件，表单字段名为 file
+func UploadMediaHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, ctx.Config.Media.MaxSize+multipartOverhead)
+
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				response.Error(w, errorx.NewParamError("文件过大"))
+				return
+			}
+			response.ParamError(w, err)
+			return
+		}
+		defer file.Close()
+		defer r.MultipartForm.RemoveAll()
+
+		l := logic.NewMediaLogic(r.Context(), ctx)
+		resp, err := l.Upload(file, header.Filename)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, resp)
+	}
+}
+
+func ListMediaHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.PageRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewMediaLogic(r.Context(), ctx)
+		resp, err := l.List(&req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, resp)
+	}
+}
+
+func DeleteMediaHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.IDRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewMediaLogic(r.Context(), ctx)
+		if err := l.Delete(req.ID); err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		response.Success(w, nil)
+	}
+}
+
+// MediaFileHandler 下载文件。存储 key 由内容哈希生成，内容不会变化，可以长期缓存
+func MediaFileHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.MediaFileRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewMediaLogic(r.Context(), ctx)
+		file, contentType, err := l.Open(&req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		defer file.Close()
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		if _, err := io.Copy(w, file); err != nil {
+			l.Logger.Errorf("write media error: %v", err)
+		}
+	}
+}
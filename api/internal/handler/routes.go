@@ -13,11 +13,12 @@ func RegisterHandlers(server *rest.Server, ctx *svc.ServiceContext) {
 	corsMiddleware := middleware.NewCorsMiddleware()
 	loggingMiddleware := middleware.NewLoggingMiddleware()
 	authMiddleware := middleware.NewAuthMiddleware(ctx.Config.Auth.AccessSecret)
+	adminMiddleware := middleware.NewAdminMiddleware(ctx.DB)
 
-	// 公开路由（无需认证）
+	// 公开路由（无需认证，携带令牌时识别当前用户）
 	server.AddRoutes(
 		rest.WithMiddlewares(
-			[]rest.Middleware{corsMiddleware.Handle, loggingMiddleware.Handle},
+			[]rest.Middleware{corsMiddleware.Handle, loggingMiddleware.Handle, authMiddleware.OptionalHandle},
 			[]rest.Route{
 				// 认证接口
 				{
@@ -41,11 +42,134 @@ func RegisterHandlers(server *rest.Server, ctx *svc.ServiceContext) {
 					Path:    "/api/v1/articles",
 					Handler: ListArticleHandler(ctx),
 				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/api/v1/articles/trending",
+					Handler: TrendingArticlesHandler(ctx),
+				},
 				{
 					Method:  http.MethodGet,
 					Path:    "/api/v1/articles/:id",
 					Handler: GetArticleHandler(ctx),
 				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/api/v1/articles/by-slug/:slug",
+					Handler: GetArticleBySlugHandler(ctx),
+				},
+				// 订阅源与站点地图
+				{
+					Method:  http.MethodGet,
+					Path:    "/feed.xml",
+					Handler: RSSFeedHandler(ctx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/atom.xml",
+					Handler: AtomFeedHandler(ctx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/feed.json",
+					Handler: JSONFeedHandler(ctx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/sitemap.xml",
+					Handler: SitemapHandler(ctx),
+				},
+				// 本地存储的上传文件
+				{
+					Method:  http.MethodGet,
+					Path:    "/uploads/media/:shard/:name",
+					Handler: MediaFileHandler(ctx),
+				},
+				// 用户主页
+				{
+					Method:  http.MethodGet,
+					Path:    "/api/v1/users/:id",
+					Handler: GetUserProfileHandler(ctx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/api/v1/users/:id/followers",
+					Handler: ListFollowersHandler(ctx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/api/v1/users/:id/following",
+					Handler: ListFollowingHandler(ctx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/api/v1/articles/:id/versions",
+					Handler: GetVersionsHandler(ctx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/api/v1/articles/:id/versions/:a/compare/:b",
+					Handler: CompareVersionsHandler(ctx),
+				},
+				// 标签与分类
+				{
+					Method:  http.MethodGet,
+					Path:    "/api/v1/tags",
+					Handler: ListTagHandler(ctx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/api/v1/categories",
+					Handler: ListCategoryHandler(ctx),
+				},
+				// 阅读上报
+				{
+					Method:  http.MethodPost,
+					Path:    "/api/v1/articles/:id/beacon",
+					Handler: ArticleBeaconHandler(ctx),
+				},
+				// 评论
+				{
+					Method:  http.MethodGet,
+					Path:    "/api/v1/articles/:id/comments",
+					Handler: ListCommentsHandler(ctx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/api/v1/comments/:id/replies",
+					Handler: ListCommentRepliesHandler(ctx),
+				},
+				// 文章系列
+				{
+					Method:  http.MethodGet,
+					Path:    "/api/v1/series",
+					Handler: ListSeriesHandler(ctx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/api/v1/series/:id",
+					Handler: GetSeriesHandler(ctx),
+				},
+			}...,
+		),
+	)
+
+	// 需要认证的路由
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{corsMiddleware.Handle, loggingMiddleware.Handle, authMiddleware.Handle},
+			[]rest.Route{
+				// 用户接口
+				{
+					Method:  http.MethodGet,
+					Path:    "/api/v1/user/info",
+					Handler: GetUserInfoHandler(ctx),
+				},
+				{
+					Method:  http.MethodPut,
+					Path:    "/api/v1/user/info",
+					Handler: UpdateUserInfoHandler(ctx),
+				},
+				// 文章编辑
 				{
 					Method:  http.MethodPost,
 					Path:    "/api/v1/articles",
@@ -61,6 +185,169 @@ func RegisterHandlers(server *rest.Server, ctx *svc.ServiceContext) {
 					Path:    "/api/v1/articles/:id",
 					Handler: DeleteArticleHandler(ctx),
 				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/api/v1/articles/:id/versions/:versionId/restore",
+					Handler: RestoreVersionHandler(ctx),
+				},
+				// 审核流程
+				{
+					Method:  http.MethodPost,
+					Path:    "/api/v1/articles/:id/submit",
+					Handler: SubmitArticleHandler(ctx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/api/v1/articles/:id/withdraw",
+					Handler: WithdrawArticleHandler(ctx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/api/v1/articles/:id/approve",
+					Handler: ApproveArticleHandler(ctx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/api/v1/articles/:id/reject",
+					Handler: RejectArticleHandler(ctx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/api/v1/articles/:id/transitions",
+					Handler: ListTransitionsHandler(ctx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/api/v1/review/queue",
+					Handler: ReviewQueueHandler(ctx),
+				},
+				// 定时发布
+				{
+					Method:  http.MethodPut,
+					Path:    "/api/v1/articles/:id/schedule",
+					Handler: SetArticleScheduleHandler(ctx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/api/v1/user/schedule",
+					Handler: ListScheduleHandler(ctx),
+				},
+				// 访问统计
+				{
+					Method:  http.MethodGet,
+					Path:    "/api/v1/articles/:id/stats",
+					Handler: ArticleStatsHandler(ctx),
+				},
+				// 点赞、表情回应与收藏
+				{
+					Method:  http.MethodPut,
+					Path:    "/api/v1/articles/:id/like",
+					Handler: LikeArticleHandler(ctx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/api/v1/articles/:id/like",
+					Handler: UnlikeArticleHandler(ctx),
+				},
+				{
+					Method:  http.MethodPut,
+					Path:    "/api/v1/articles/:id/reactions/:emoji",
+					Handler: ReactArticleHandler(ctx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/api/v1/articles/:id/reactions/:emoji",
+					Handler: UnreactArticleHandler(ctx),
+				},
+				{
+					Method:  http.MethodPut,
+					Path:    "/api/v1/articles/:id/bookmark",
+					Handler: BookmarkArticleHandler(ctx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/api/v1/articles/:id/bookmark",
+					Handler: UnbookmarkArticleHandler(ctx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/api/v1/user/bookmarks",
+					Handler: ListBookmarksHandler(ctx),
+				},
+				// 关注与动态
+				{
+					Method:  http.MethodPut,
+					Path:    "/api/v1/users/:id/follow",
+					Handler: FollowUserHandler(ctx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/api/v1/users/:id/follow",
+					Handler: UnfollowUserHandler(ctx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/api/v1/feed",
+					Handler: FeedHandler(ctx),
+				},
+				// 文件
+				{
+					Method:  http.MethodGet,
+					Path:    "/api/v1/media",
+					Handler: ListMediaHandler(ctx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/api/v1/media/:id",
+					Handler: DeleteMediaHandler(ctx),
+				},
+				// 评论与通知
+				{
+					Method:  http.MethodPost,
+					Path:    "/api/v1/articles/:id/comments",
+					Handler: CreateCommentHandler(ctx),
+				},
+				{
+					Method:  http.MethodPut,
+					Path:    "/api/v1/comments/:id",
+					Handler: UpdateCommentHandler(ctx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/api/v1/comments/:id",
+					Handler: DeleteCommentHandler(ctx),
+				},
+				{
+					Method:  http.MethodPut,
+					Path:    "/api/v1/comments/:id/pin",
+					Handler: PinCommentHandler(ctx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/api/v1/comments/:id/pin",
+					Handler: UnpinCommentHandler(ctx),
+				},
+				{
+					Method:  http.MethodPut,
+					Path:    "/api/v1/comments/:id/status",
+					Handler: SetCommentStatusHandler(ctx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/api/v1/notifications",
+					Handler: ListNotificationsHandler(ctx),
+				},
+				{
+					Method:  http.MethodPut,
+					Path:    "/api/v1/notifications/:id/read",
+					Handler: ReadNotificationHandler(ctx),
+				},
+				{
+					Method:  http.MethodPut,
+					Path:    "/api/v1/notifications/read",
+					Handler: ReadAllNotificationsHandler(ctx),
+				},
+				// 草稿（按用户区分，需要登录）
 				{
 					Method:  http.MethodPost,
 					Path:    "/api/v1/articles/draft",
@@ -68,33 +355,167 @@ func RegisterHandlers(server *rest.Server, ctx *svc.ServiceContext) {
 				},
 				{
 					Method:  http.MethodGet,
-					Path:    "/api/v1/articles/:id/versions",
-					Handler: GetVersionsHandler(ctx),
+					Path:    "/api/v1/drafts",
+					Handler: ListDraftHandler(ctx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/api/v1/drafts/:id",
+					Handler: GetDraftHandler(ctx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/api/v1/drafts/:id",
+					Handler: DeleteDraftHandler(ctx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/api/v1/drafts/:id/snapshots",
+					Handler: ListDraftSnapshotsHandler(ctx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/api/v1/drafts/:id/snapshots/:snapshotId",
+					Handler: GetDraftSnapshotHandler(ctx),
 				},
 				{
 					Method:  http.MethodPost,
-					Path:    "/api/v1/articles/:id/versions/:versionId/restore",
-					Handler: RestoreVersionHandler(ctx),
+					Path:    "/api/v1/drafts/:id/snapshots/:snapshotId/restore",
+					Handler: RestoreDraftSnapshotHandler(ctx),
+				},
+				// 文章系列管理
+				{
+					Method:  http.MethodGet,
+					Path:    "/api/v1/user/series",
+					Handler: ListMySeriesHandler(ctx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/api/v1/series",
+					Handler: CreateSeriesHandler(ctx),
+				},
+				{
+					Method:  http.MethodPut,
+					Path:    "/api/v1/series/:id",
+					Handler: UpdateSeriesHandler(ctx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/api/v1/series/:id",
+					Handler: DeleteSeriesHandler(ctx),
+				},
+				{
+					Method:  http.MethodPut,
+					Path:    "/api/v1/series/:id/articles",
+					Handler: SetSeriesArticlesHandler(ctx),
+				},
+				// 批量操作
+				{
+					Method:  http.MethodPost,
+					Path:    "/api/v1/articles/batch",
+					Handler: BatchArticlesHandler(ctx),
+				},
+				// 回收站
+				{
+					Method:  http.MethodGet,
+					Path:    "/api/v1/trash",
+					Handler: ListTrashHandler(ctx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/api/v1/trash/:id/restore",
+					Handler: RestoreTrashHandler(ctx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/api/v1/trash/:id",
+					Handler: PurgeTrashHandler(ctx),
+				},
+				// 协作者
+				{
+					Method:  http.MethodGet,
+					Path:    "/api/v1/articles/:id/collaborators",
+					Handler: ListCollaboratorsHandler(ctx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/api/v1/articles/:id/collaborators",
+					Handler: InviteCollaboratorHandler(ctx),
+				},
+				{
+					Method:  http.MethodPut,
+					Path:    "/api/v1/articles/:id/collaborators/:userId",
+					Handler: UpdateCollaboratorHandler(ctx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/api/v1/articles/:id/collaborators/:userId",
+					Handler: RemoveCollaboratorHandler(ctx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/api/v1/user/invitations",
+					Handler: ListInvitationsHandler(ctx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/api/v1/invitations/:id/accept",
+					Handler: AcceptInvitationHandler(ctx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/api/v1/invitations/:id/decline",
+					Handler: DeclineInvitationHandler(ctx),
 				},
 			}...,
 		),
 	)
 
-	// 需要认证的路由
+	// 文件上传，请求体大小和超时时间单独配置
 	server.AddRoutes(
 		rest.WithMiddlewares(
 			[]rest.Middleware{corsMiddleware.Handle, loggingMiddleware.Handle, authMiddleware.Handle},
+			rest.Route{
+				Method:  http.MethodPost,
+				Path:    "/api/v1/media",
+				Handler: UploadMediaHandler(ctx),
+			},
+		),
+		rest.WithMaxBytes(ctx.Config.Media.MaxSize+multipartOverhead),
+		rest.WithTimeout(ctx.Config.Media.UploadTimeout),
+	)
+
+	// 管理员路由
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{corsMiddleware.Handle, loggingMiddleware.Handle, authMiddleware.Handle, adminMiddleware.Handle},
 			[]rest.Route{
-				// 用户接口
+				// 标签管理
 				{
-					Method:  http.MethodGet,
-					Path:    "/api/v1/user/info",
-					Handler: GetUserInfoHandler(ctx),
+					Method:  http.MethodPut,
+					Path:    "/api/v1/admin/tags/:id",
+					Handler: RenameTagHandler(ctx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/api/v1/admin/tags/merge",
+					Handler: MergeTagsHandler(ctx),
+				},
+				// 分类管理
+				{
+					Method:  http.MethodPost,
+					Path:    "/api/v1/admin/categories",
+					Handler: CreateCategoryHandler(ctx),
 				},
 				{
 					Method:  http.MethodPut,
-					Path:    "/api/v1/user/info",
-					Handler: UpdateUserInfoHandler(ctx),
+					Path:    "/api/v1/admin/categories/:id",
+					Handler: UpdateCategoryHandler(ctx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/api/v1/admin/categories/:id",
+					Handler: DeleteCategoryHandler(ctx),
 				},
 			}...,
 		),
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"acupofcoffee/api/internal/logic"
+	"acupofcoffee/api/internal/svc"
+	"acupofcoffee/common/errorx"
+	"acupofcoffee/common/response"
+	"acupofcoffee/common/sitemap"
+)
+
+// SitemapHandler 不带 page 参数时输出站点地图索引，带 page 时输出对应的一页
+func SitemapHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logic.NewSitemapLogic(r.Context(), ctx)
+
+		var (
+			entries []sitemap.Entry
+			modtime time.Time
+			body    []byte
+			err     error
+		)
+		if v := r.URL.Query().Get("page"); v != "" {
+			page, convErr := strconv.Atoi(v)
+			if convErr != nil {
+				response.Error(w, errorx.NewParamError("页码格式错误"))
+				return
+			}
+			if entries, modtime, err = l.Page(page); err == nil {
+				body, err = sitemap.URLSet(entries)
+			}
+		} else {
+			if entries, modtime, err = l.Index(); err == nil {
+				body, err = sitemap.Index(entries)
+			}
+		}
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		serveConditional(w, r, sitemap.ContentType, body, modtime, ctx.Config.Sitemap.MaxAge)
+	}
+}
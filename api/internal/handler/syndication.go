@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+
+	"acupofcoffee/api/internal/logic"
+	"acupofcoffee/api/internal/svc"
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/response"
+	"acupofcoffee/common/syndication"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func RSSFeedHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return syndicationHandler(ctx, syndication.ContentTypeRSS, syndication.RSS)
+}
+
+func AtomFeedHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return syndicationHandler(ctx, syndication.ContentTypeAtom, syndication.Atom)
+}
+
+func JSONFeedHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return syndicationHandler(ctx, syndication.ContentTypeJSON, syndication.JSON)
+}
+
+// syndicationHandler 输出订阅源，Last-Modified 取最近一次修改文章的时间
+func syndicationHandler(ctx *svc.ServiceContext, contentType string,
+	encode func(*syndication.Feed) ([]byte, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.SyndicationRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err)
+			return
+		}
+
+		l := logic.NewSyndicationLogic(r.Context(), ctx)
+		feed, err := l.Feed(&req, r.URL.RequestURI())
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		body, err := encode(feed)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		serveConditional(w, r, contentType, body, feed.Updated, ctx.Config.Syndication.MaxAge)
+	}
+}
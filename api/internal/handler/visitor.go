@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"acupofcoffee/api/internal/types"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// visitorFromRequest 提取访问者的 IP 和 User-Agent，经过代理时取 X-Forwarded-For 的第一个地址
+func visitorFromRequest(r *http.Request) *types.Visitor {
+	addr := httpx.GetRemoteAddr(r)
+	if i := strings.IndexByte(addr, ','); i >= 0 {
+		addr = addr[:i]
+	}
+	addr = strings.TrimSpace(addr)
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+
+	return &types.Visitor{
+		IP:        addr,
+		UserAgent: r.UserAgent(),
+	}
+}
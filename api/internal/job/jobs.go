@@ -0,0 +1,83 @@
+package job
+
+import (
+	"context"
+
+	"acupofcoffee/api/internal/logic"
+	"acupofcoffee/api/internal/svc"
+)
+
+// RegisterJobs 注册所有后台任务
+func RegisterJobs(runner *Runner, svcCtx *svc.ServiceContext) {
+	// 定时发布/下线文章
+	runner.Add(Job{
+		Name:     "article-schedule",
+		Interval: svcCtx.Config.Job.ScheduleInterval,
+		Run: func(ctx context.Context) error {
+			return logic.NewScheduleLogic(ctx, svcCtx).RunDue()
+		},
+	})
+
+	// 按保留策略压缩文章历史版本
+	runner.Add(Job{
+		Name:     "version-compact",
+		Interval: svcCtx.Config.Job.CompactInterval,
+		Run: func(ctx context.Context) error {
+			return logic.NewVersionLogic(ctx, svcCtx).Compact()
+		},
+	})
+
+	// 按点赞记录校正文章计数
+	runner.Add(Job{
+		Name:     "article-counters",
+		Interval: svcCtx.Config.Job.CounterInterval,
+		Run: func(ctx context.Context) error {
+			return logic.NewReactionLogic(ctx, svcCtx).ReconcileCounters()
+		},
+	})
+
+	// 按关注记录校正用户的粉丝数和关注数
+	runner.Add(Job{
+		Name:     "follow-counters",
+		Interval: svcCtx.Config.Job.CounterInterval,
+		Run: func(ctx context.Context) error {
+			return logic.NewFollowLogic(ctx, svcCtx).ReconcileCounters()
+		},
+	})
+
+	// 清理过期的访客去重记录
+	runner.Add(Job{
+		Name:     "analytics-prune",
+		Interval: svcCtx.Config.Job.PruneInterval,
+		Run: func(ctx context.Context) error {
+			return logic.NewAnalyticsLogic(ctx, svcCtx).PruneVisitors()
+		},
+	})
+
+	// 热度排行只保留排名靠前的文章
+	runner.Add(Job{
+		Name:     "article-ranking",
+		Interval: svcCtx.Config.Job.RankingInterval,
+		Run: func(ctx context.Context) error {
+			return svcCtx.Ranking.Trim()
+		},
+	})
+
+	// 重新排队未生成变体的图片
+	runner.Add(Job{
+		Name:     "media-variants",
+		Interval: svcCtx.Config.Job.MediaInterval,
+		Run: func(ctx context.Context) error {
+			return logic.NewMediaLogic(ctx, svcCtx).RequeueImages()
+		},
+	})
+
+	// 永久删除回收站中超过保留期的文章
+	runner.Add(Job{
+		Name:     "trash-purge",
+		Interval: svcCtx.Config.Job.TrashInterval,
+		Run: func(ctx context.Context) error {
+			return logic.NewTrashLogic(ctx, svcCtx).PurgeExpired()
+		},
+	})
+}
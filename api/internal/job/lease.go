@@ -0,0 +1,46 @@
+package job
+
+import (
+	"time"
+
+	"acupofcoffee/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// acquireLease 获取或续期任务租约，租约过期前其他实例无法获取
+func acquireLease(db *gorm.DB, name, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+
+	// 自己持有或已过期：续期/抢占
+	result := db.Model(&model.JobLease{}).
+		Where("name = ? AND (owner = ? OR expires_at < ?)", name, owner, now).
+		Updates(map[string]interface{}{
+			"owner":      owner,
+			"expires_at": now.Add(ttl),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+
+	// 租约不存在：尝试创建，并发创建时只有一个实例成功
+	result = db.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.JobLease{
+		Name:      name,
+		Owner:     owner,
+		ExpiresAt: now.Add(ttl),
+	})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// releaseLease 释放自己持有的租约，便于其他实例立即接管
+func releaseLease(db *gorm.DB, name, owner string) error {
+	return db.Where("name = ? AND owner = ?", name, owner).Delete(&model.JobLease{}).Error
+}
@@ -0,0 +1,107 @@
+package job
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"acupofcoffee/api/internal/svc"
+	"acupofcoffee/common/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// Job 周期性后台任务
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Runner 在 API 进程内运行后台任务，每次执行前获取数据库租约，
+// 保证多副本部署时同一任务同一时间只在一个实例上执行
+type Runner struct {
+	svcCtx *svc.ServiceContext
+	owner  string
+	jobs   []Job
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewRunner(svcCtx *svc.ServiceContext) *Runner {
+	hostname, _ := os.Hostname()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Runner{
+		svcCtx: svcCtx,
+		owner:  hostname + "-" + utils.GenerateRandomString(8),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Add 注册任务，需在 Start 之前调用
+func (r *Runner) Add(job Job) {
+	r.jobs = append(r.jobs, job)
+}
+
+// Start 启动所有任务
+func (r *Runner) Start() {
+	for _, job := range r.jobs {
+		r.wg.Add(1)
+		go r.loop(job)
+	}
+}
+
+// Stop 停止所有任务并释放租约
+func (r *Runner) Stop() {
+	r.cancel()
+	r.wg.Wait()
+
+	for _, job := range r.jobs {
+		if err := releaseLease(r.svcCtx.DB, job.Name, r.owner); err != nil {
+			logx.Errorf("release lease %s error: %v", job.Name, err)
+		}
+	}
+}
+
+func (r *Runner) loop(job Job) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+			r.runOnce(job)
+		}
+	}
+}
+
+func (r *Runner) runOnce(job Job) {
+	defer func() {
+		if p := recover(); p != nil {
+			logx.Errorf("job %s panic: %v", job.Name, p)
+		}
+	}()
+
+	// 租约时长覆盖若干个周期，持有者每个周期续期，宕机后由其他实例接管
+	ok, err := acquireLease(r.svcCtx.DB, job.Name, r.owner, job.Interval*3)
+	if err != nil {
+		logx.Errorf("acquire lease %s error: %v", job.Name, err)
+		return
+	}
+	if !ok {
+		return
+	}
+
+	if err := job.Run(r.ctx); err != nil {
+		logx.Errorf("job %s error: %v", job.Name, err)
+	}
+}
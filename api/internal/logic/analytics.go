@@ -0,0 +1,205 @@
+package logic
+
+import (
+	"context"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"acupofcoffee/api/internal/svc"
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/errorx"
+	"acupofcoffee/common/utils"
+	"acupofcoffee/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultStatsDays = 30 // 不指定范围时统计最近 30 天
+	maxReferrers     = 20 // 返回的来源域名数量
+)
+
+type AnalyticsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewAnalyticsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *AnalyticsLogic {
+	return &AnalyticsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// Beacon 读者上报来源和阅读进度，爬虫以及作者和协作者本人的上报忽略
+func (l *AnalyticsLogic) Beacon(req *types.ArticleBeaconRequest, visitor *types.Visitor) error {
+	if req.Depth < 0 || req.Depth > 100 {
+		return errorx.NewParamError("阅读进度应在 0-100 之间")
+	}
+
+	var article model.Article
+	if err := l.svcCtx.DB.Select("id", "author_id").
+		Where("status = ?", model.ArticleStatusPublished).
+		First(&article, req.ID).Error; err != nil {
+		return errorx.NewNotFoundError("文章不存在")
+	}
+
+	userID, _ := l.ctx.Value("userId").(uint)
+	if utils.IsBot(visitor.UserAgent) || articleRole(l.svcCtx.DB, &article, userID) != "" {
+		return nil
+	}
+
+	l.svcCtx.Analytics.RecordBeacon(article.ID, visitorKey(userID, visitor), referrerDomain(req.Referrer), req.Depth)
+	return nil
+}
+
+// Stats 文章按天的访问统计，仅作者、共同作者和管理员可查看
+func (l *AnalyticsLogic) Stats(req *types.ArticleStatsRequest) (*types.ArticleStatsResponse, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+
+	var article model.Article
+	if err := l.svcCtx.DB.Select("id", "author_id").First(&article, req.ID).Error; err != nil {
+		return nil, errorx.NewNotFoundError("文章不存在")
+	}
+	if !isAuthorOrAdmin(l.svcCtx.DB, &article, userID) {
+		return nil, errorx.NewForbiddenError("无权查看该文章的统计")
+	}
+
+	loc := l.svcCtx.Analytics.Location()
+	to := utils.GetStartOfDay(time.Now().In(loc))
+	if req.To != "" {
+		t, err := time.ParseInLocation(utils.DateFormat, req.To, loc)
+		if err != nil {
+			return nil, errorx.NewParamError("结束日期格式错误，应为 2006-01-02")
+		}
+		to = t
+	}
+	from := to.AddDate(0, 0, -(defaultStatsDays - 1))
+	if req.From != "" {
+		t, err := time.ParseInLocation(utils.DateFormat, req.From, loc)
+		if err != nil {
+			return nil, errorx.NewParamError("开始日期格式错误，应为 2006-01-02")
+		}
+		from = t
+	}
+
+	days := utils.DaysBetween(from, to) + 1
+	if days <= 0 {
+		return nil, errorx.NewParamError("开始日期不能晚于结束日期")
+	}
+	if days > l.svcCtx.Config.Analytics.MaxRangeDays {
+		return nil, errorx.NewParamError("查询范围不能超过 " + strconv.Itoa(l.svcCtx.Config.Analytics.MaxRangeDays) + " 天")
+	}
+
+	fromDay, toDay := from.Format(utils.DateFormat), to.Format(utils.DateFormat)
+
+	var stats []model.ArticleDailyStat
+	if err := l.svcCtx.DB.Where("article_id = ? AND day BETWEEN ? AND ?", article.ID, fromDay, toDay).
+		Find(&stats).Error; err != nil {
+		l.Logger.Errorf("query article stats error: %v", err)
+		return nil, errorx.NewDefaultError("获取统计失败")
+	}
+	byDay := make(map[string]model.ArticleDailyStat, len(stats))
+	for _, s := range stats {
+		byDay[s.Day] = s
+	}
+
+	resp := &types.ArticleStatsResponse{
+		ArticleID: article.ID,
+		Timezone:  loc.String(),
+		From:      fromDay,
+		To:        toDay,
+		Series:    make([]*types.ArticleStatsPoint, days),
+		Referrers: make([]*types.ReferrerStat, 0),
+	}
+
+	// 没有数据的日期补零，保证序列连续
+	var depthTotal int64
+	for i := 0; i < days; i++ {
+		day := from.AddDate(0, 0, i).Format(utils.DateFormat)
+		s := byDay[day]
+		resp.Series[i] = &types.ArticleStatsPoint{
+			Date:         day,
+			Views:        s.Views,
+			Visitors:     s.Visitors,
+			Reads:        s.ReadCount,
+			AvgReadDepth: averageDepth(s.ReadDepthTotal, s.ReadCount),
+		}
+		resp.Total.Views += s.Views
+		resp.Total.Visitors += s.Visitors
+		resp.Total.Reads += s.ReadCount
+		depthTotal += s.ReadDepthTotal
+	}
+	resp.Total.AvgReadDepth = averageDepth(depthTotal, resp.Total.Reads)
+
+	if err := l.svcCtx.DB.Model(&model.ArticleDailyReferrer{}).
+		Select("domain, SUM(visits) AS visits").
+		Where("article_id = ? AND day BETWEEN ? AND ?", article.ID, fromDay, toDay).
+		Group("domain").
+		Order("visits DESC").
+		Limit(maxReferrers).
+		Scan(&resp.Referrers).Error; err != nil {
+		l.Logger.Errorf("query article referrers error: %v", err)
+		return nil, errorx.NewDefaultError("获取统计失败")
+	}
+
+	return resp, nil
+}
+
+// PruneVisitors 清理前一天之前的访客去重记录，由后台任务周期调用
+func (l *AnalyticsLogic) PruneVisitors() error {
+	yesterday := l.svcCtx.Analytics.Day(time.Now().AddDate(0, 0, -1))
+
+	result := l.svcCtx.DB.Where("day < ?", yesterday).Delete(&model.ArticleDailyVisitor{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected > 0 {
+		l.Logger.Infof("prune visitors: %d records removed", result.RowsAffected)
+	}
+	return nil
+}
+
+// visitorKey 访客标识：登录用户按用户区分，匿名访客按 IP 和 User-Agent 区分
+func visitorKey(userID uint, visitor *types.Visitor) string {
+	if userID != 0 {
+		return "u:" + strconv.FormatUint(uint64(userID), 10)
+	}
+	return "ip:" + visitor.IP + "|" + visitor.UserAgent
+}
+
+// referrerDomain 提取来源域名，去掉 www. 前缀，无法识别时视为直接访问
+func referrerDomain(referrer string) string {
+	u, err := url.Parse(strings.TrimSpace(referrer))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if len(host) > 255 {
+		return ""
+	}
+	return host
+}
+
+func averageDepth(total, count int64) float64 {
+	if count == 0 {
+		return 0
+	}
+	return math.Round(float64(total)/float64(count)*10) / 10
+}
+
+// isAuthorOrAdmin 文章作者（含共同作者）或管理员
+func isAuthorOrAdmin(db *gorm.DB, article *model.Article, userID uint) bool {
+	return isArticleAuthor(db, article, userID) || isAdmin(db, userID)
+}
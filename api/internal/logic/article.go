@@ -2,17 +2,38 @@ package logic
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
+	"acupofcoffee/api/internal/config"
 	"acupofcoffee/api/internal/svc"
 	"acupofcoffee/api/internal/types"
 	"acupofcoffee/common/errorx"
+	"acupofcoffee/common/richtext"
+	"acupofcoffee/common/utils"
 	"acupofcoffee/model"
 
 	"github.com/zeromicro/go-zero/core/logx"
 	"gorm.io/gorm"
 )
 
+// 文章列表排序方式
+const (
+	articleSortLatest   = "latest"
+	articleSortOldest   = "oldest"
+	articleSortUpdated  = "updated"
+	articleSortTrending = "trending"
+	articleSortViews    = "views"
+	articleSortLikes    = "likes"
+)
+
+// 文章列表每页最大条数，与 model.Paginate 一致
+const maxArticlePageSize = 100
+
 type ArticleLogic struct {
 	logx.Logger
 	ctx    context.Context
@@ -31,60 +52,176 @@ func NewArticleLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ArticleLo
 func (l *ArticleLogic) Create(req *types.CreateArticleRequest) (*types.ArticleResponse, error) {
 	userID, ok := l.ctx.Value("userId").(uint)
 	if !ok {
-		userID = 1 // 开发模式：默认用户ID
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+
+	publishAt, unpublishAt, err := parseSchedule(req.PublishAt, req.UnpublishAt)
+	if err != nil {
+		return nil, err
+	}
+	if publishAt != nil {
+		// 定时发布的文章在发布时间之前保持草稿状态
+		req.Status = model.ArticleStatusDraft
+	}
+
+	// 新文章从草稿开始，可以直接提交审核或发布（受审核流程约束）
+	status := req.Status
+	switch status {
+	case model.ArticleStatusDraft, model.ArticleStatusInReview, model.ArticleStatusPublished:
+	default:
+		return nil, errorx.NewParamError("文章状态错误")
+	}
+
+	// 未填写摘要时从正文生成，之后随正文更新
+	summary, summaryGenerated := req.Summary, false
+	if summary == "" {
+		summary, summaryGenerated = generateSummary(l.svcCtx.Config.Summary, req.Content), true
 	}
 
 	article := model.Article{
-		Title:      req.Title,
-		Content:    req.Content,
-		ContentRaw: req.Content, // 简化：直接使用 content 作为搜索内容
-		Cover:      req.Cover,
-		Summary:    req.Summary,
-		AuthorID:   userID,
-		Status:     req.Status,
-		Version:    1,
+		Title:       req.Title,
+		Content:     req.Content,
+		ContentRaw:  req.Content, // 简化：直接使用 content 作为搜索内容
+		Cover:       req.Cover,
+		Summary:     summary,
+		AuthorID:    userID,
+		EditorID:    userID,
+		Status:      model.ArticleStatusDraft,
+		Version:     1,
+		PublishAt:   publishAt,
+		UnpublishAt: unpublishAt,
+
+		SummaryGenerated: summaryGenerated,
+	}
+
+	actors := articleActors(l.svcCtx.DB, &article, userID)
+	if status != model.ArticleStatusDraft {
+		if err := checkTransition(l.svcCtx.Config.Workflow, model.ArticleStatusDraft, status, actors); err != nil {
+			return nil, err
+		}
+	}
+	// 到期后由后台任务直接发布，创建时就要求具备发布权限；需要审核的文章审核通过后再设置定时
+	if publishAt != nil || unpublishAt != nil {
+		if err := checkTransition(l.svcCtx.Config.Workflow, model.ArticleStatusDraft, model.ArticleStatusPublished, actors); err != nil {
+			return nil, err
+		}
 	}
 
-	if err := l.svcCtx.DB.Create(&article).Error; err != nil {
+	err = l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		tags, err := resolveTags(tx, req.Tags)
+		if err != nil {
+			return err
+		}
+		categories, err := resolveCategories(tx, req.CategoryIDs)
+		if err != nil {
+			return err
+		}
+		article.Tags = tags
+		article.Categories = categories
+
+		if req.Slug != "" {
+			if err := validateCustomSlug(tx, req.Slug, 0); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Create(&article).Error; err != nil {
+			return err
+		}
+		if status != model.ArticleStatusDraft {
+			if err := transitionArticle(tx, &article, status, userID, ""); err != nil {
+				return err
+			}
+		}
+
+		slug := req.Slug
+		if slug == "" {
+			if slug, err = generateArticleSlug(tx, article.Title, article.ID); err != nil {
+				return err
+			}
+		}
+		if err := assignArticleSlug(tx, &article, slug); err != nil {
+			return err
+		}
+
+		return syncMediaReferences(tx, l.svcCtx.Config.Site, article.ID)
+	})
+	if err != nil {
+		if codeErr, ok := err.(*errorx.CodeError); ok {
+			return nil, codeErr
+		}
 		l.Logger.Errorf("create article error: %v", err)
 		return nil, errorx.NewDefaultError("创建文章失败")
 	}
 
-	// 删除草稿（如果存在）
-	l.svcCtx.DB.Where("article_id = 0 AND user_id = ?", userID).Delete(&model.ArticleDraft{})
+	// 从草稿创建的，删除该草稿
+	if req.DraftID > 0 {
+		var draft model.ArticleDraft
+		if l.svcCtx.DB.Where("id = ? AND article_id = 0 AND user_id = ?", req.DraftID, userID).
+			First(&draft).Error == nil {
+			purgeDrafts(l.svcCtx.DB, []uint{draft.ID})
+		}
+	}
 
 	return l.articleToResponse(&article), nil
 }
 
 // Update 更新文章（带版本控制）
 func (l *ArticleLogic) Update(req *types.UpdateArticleRequest) (*types.ArticleResponse, error) {
-	userID, _ := l.ctx.Value("userId").(uint)
-	// 开发模式：不检查权限
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
 
 	var article model.Article
 	if err := l.svcCtx.DB.First(&article, req.ID).Error; err != nil {
 		return nil, errorx.NewNotFoundError("文章不存在")
 	}
+	if !canEditArticle(l.svcCtx.DB, &article, userID) {
+		return nil, errorx.NewForbiddenError("无权编辑该文章")
+	}
 
-	_ = userID // 暂时忽略用户ID检查
+	// 状态变更必须符合审核流程
+	actors := articleActors(l.svcCtx.DB, &article, userID)
+	statusChanged := req.Status != 0 && req.Status != article.Status
+	if statusChanged {
+		if err := checkTransition(l.svcCtx.Config.Workflow, article.Status, req.Status, actors); err != nil {
+			return nil, err
+		}
+	}
+
+	// 修改内容可能需要重新审核；同时发布时不能带着未审核的修改直接上线
+	contentChanged := (req.Title != "" && req.Title != article.Title) ||
+		(req.Content != "" && req.Content != article.Content)
+	editStatus, editComment := article.Status, ""
+	if contentChanged && (!statusChanged || req.Status == model.ArticleStatusPublished) {
+		from := article.Status
+		if statusChanged {
+			from = req.Status
+		}
+		to, comment, err := statusAfterEdit(l.svcCtx.Config.Workflow, from, actors)
+		if err != nil {
+			return nil, err
+		}
+		if statusChanged && to != req.Status {
+			return nil, errorx.NewParamError("修改内容后需要重新审核")
+		}
+		editStatus, editComment = to, comment
+	}
 
 	// 使用事务保存版本历史和更新文章
 	err := l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
 		// 1. 保存当前版本到历史
-		version := model.ArticleVersion{
-			ArticleID: article.ID,
-			Title:     article.Title,
-			Content:   article.Content,
-			Version:   article.Version,
-			Remark:    req.Remark,
-		}
-		if err := tx.Create(&version).Error; err != nil {
+		oldTitle := article.Title
+		if err := saveArticleVersion(tx, l.svcCtx.Config.Version, &article, req.Remark); err != nil {
 			return err
 		}
 
 		// 2. 更新文章
 		updates := map[string]interface{}{
-			"version": article.Version + 1,
+			"version":       article.Version + 1,
+			"editor_id":     userID,
+			"restored_from": 0,
 		}
 		if req.Title != "" {
 			updates["title"] = req.Title
@@ -98,182 +235,853 @@ func (l *ArticleLogic) Update(req *types.UpdateArticleRequest) (*types.ArticleRe
 		}
 		if req.Summary != "" {
 			updates["summary"] = req.Summary
+			updates["summary_generated"] = false
+		} else if req.Content != "" && (article.SummaryGenerated || article.Summary == "") {
+			// 自动生成的摘要随正文更新，手动填写的保持不变
+			updates["summary"] = generateSummary(l.svcCtx.Config.Summary, req.Content)
+			updates["summary_generated"] = true
+		}
+
+		if err := tx.Model(&article).Updates(updates).Error; err != nil {
+			return err
 		}
-		if req.Status != 0 {
-			updates["status"] = req.Status
+
+		// 3. 状态流转：修改内容需要重新审核时回到对应状态
+		if statusChanged {
+			if err := transitionArticle(tx, &article, req.Status, userID, req.Remark); err != nil {
+				return err
+			}
+		} else if editStatus != article.Status {
+			if err := transitionArticle(tx, &article, editStatus, userID, editComment); err != nil {
+				return err
+			}
 		}
 
-		return tx.Model(&article).Updates(updates).Error
+		// 4. 更新永久链接：手动指定优先，否则标题变化时重新生成，旧链接保留用于跳转
+		if req.Slug != "" {
+			if err := validateCustomSlug(tx, req.Slug, article.ID); err != nil {
+				return err
+			}
+			if err := assignArticleSlug(tx, &article, req.Slug); err != nil {
+				return err
+			}
+		} else if (req.Title != "" && req.Title != oldTitle) || article.Slug == "" {
+			title := req.Title
+			if title == "" {
+				title = oldTitle
+			}
+			slug, err := generateArticleSlug(tx, title, article.ID)
+			if err != nil {
+				return err
+			}
+			if err := assignArticleSlug(tx, &article, slug); err != nil {
+				return err
+			}
+		}
+
+		// 5. 正式保存后，当前用户对该文章的草稿不再需要
+		if err := deleteArticleDrafts(tx, article.ID, userID); err != nil {
+			return err
+		}
+
+		// 6. 更新标签和分类（nil 表示不修改）
+		if req.Tags != nil {
+			tags, err := resolveTags(tx, req.Tags)
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(&article).Association("Tags").Replace(tags); err != nil {
+				return err
+			}
+		}
+		if req.CategoryIDs != nil {
+			categories, err := resolveCategories(tx, req.CategoryIDs)
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(&article).Association("Categories").Replace(categories); err != nil {
+				return err
+			}
+		}
+
+		// 7. 重建封面和正文图片对上传文件的引用
+		return syncMediaReferences(tx, l.svcCtx.Config.Site, article.ID)
 	})
 
 	if err != nil {
+		if codeErr, ok := err.(*errorx.CodeError); ok {
+			return nil, codeErr
+		}
 		l.Logger.Errorf("update article error: %v", err)
 		return nil, errorx.NewDefaultError("更新文章失败")
 	}
 
 	// 重新查询更新后的文章
-	l.svcCtx.DB.First(&article, req.ID)
-	return l.articleToResponse(&article), nil
+	l.svcCtx.DB.Preload("Author").Preload("Tags").Preload("Categories").First(&article, req.ID)
+	resp := l.articleToResponse(&article)
+	fillCoAuthors(l.svcCtx.DB, resp)
+	return resp, nil
 }
 
 // Get 获取文章详情
-func (l *ArticleLogic) Get(id uint) (*types.ArticleResponse, error) {
+func (l *ArticleLogic) Get(id uint, visitor *types.Visitor) (*types.ArticleResponse, error) {
 	var article model.Article
-	if err := l.svcCtx.DB.Preload("Author").First(&article, id).Error; err != nil {
+	if err := l.svcCtx.DB.Preload("Author").Preload("Tags").Preload("Categories").First(&article, id).Error; err != nil {
+		return nil, errorx.NewNotFoundError("文章不存在")
+	}
+
+	// 未发布的文章对无权查看的人表现为不存在
+	userID, _ := l.ctx.Value("userId").(uint)
+	if !canViewArticle(l.svcCtx.DB, &article, userID) {
 		return nil, errorx.NewNotFoundError("文章不存在")
 	}
 
-	// 增加浏览量
-	l.svcCtx.DB.Model(&article).UpdateColumn("view_count", gorm.Expr("view_count + 1"))
+	l.recordView(&article, userID, visitor)
 
-	return l.articleToResponse(&article), nil
+	resp := l.articleToResponse(&article)
+	resp.ViewCount += l.svcCtx.Views.Pending(article.ID)
+
+	// 系列导航：作者和协作者以外的人只能看到已发布的文章
+	resp.Series = articleSeriesNav(l.svcCtx.DB, &article, articleRole(l.svcCtx.DB, &article, userID) == "")
+
+	fillViewerState(l.svcCtx.DB, userID, resp)
+	fillCoverSrcset(l.svcCtx.DB, l.svcCtx.Config.Site, resp)
+	fillCoAuthors(l.svcCtx.DB, resp)
+	if reactions, err := articleReactions(l.svcCtx.DB, article.ID, userID); err == nil {
+		resp.Reactions = reactions
+	}
+	resp.SEO = articleSEO(l.svcCtx.Config.Site, &article)
+
+	return resp, nil
 }
 
-// List 文章列表
-func (l *ArticleLogic) List(req *types.ArticleListRequest) (*types.ArticleListResponse, error) {
-	var articles []model.Article
-	var total int64
+// viewableArticle 加载当前用户可以查看的文章
+func (l *ArticleLogic) viewableArticle(id uint) (*model.Article, error) {
+	var article model.Article
+	if err := l.svcCtx.DB.First(&article, id).Error; err != nil {
+		return nil, errorx.NewNotFoundError("文章不存在")
+	}
+	userID, _ := l.ctx.Value("userId").(uint)
+	if !canViewArticle(l.svcCtx.DB, &article, userID) {
+		return nil, errorx.NewNotFoundError("文章不存在")
+	}
+	return &article, nil
+}
 
-	query := l.svcCtx.DB.Model(&model.Article{})
+// recordView 记录浏览，只统计已发布文章，作者、协作者和爬虫不计入
+func (l *ArticleLogic) recordView(article *model.Article, userID uint, visitor *types.Visitor) {
+	if article.Status != model.ArticleStatusPublished || visitor == nil || utils.IsBot(visitor.UserAgent) {
+		return
+	}
+	if articleRole(l.svcCtx.DB, article, userID) != "" {
+		return
+	}
 
-	if req.Status != nil {
-		query = query.Where("status = ?", *req.Status)
+	key := visitorKey(userID, visitor)
+	if l.svcCtx.Views.Record(article.ID, key) {
+		l.svcCtx.Analytics.RecordView(article.ID, key)
+		l.svcCtx.Ranking.View(article.ID)
 	}
-	if req.AuthorID > 0 {
-		query = query.Where("author_id = ?", req.AuthorID)
+}
+
+// ResolveSlug 根据 slug 查找文章，返回文章ID和当前 slug；
+// 请求的是历史 slug 时两者不同，调用方应跳转到当前 slug
+func (l *ArticleLogic) ResolveSlug(slug string) (uint, string, error) {
+	var record model.ArticleSlug
+	if err := l.svcCtx.DB.Where("slug = ?", slug).First(&record).Error; err != nil {
+		return 0, "", errorx.NewNotFoundError("文章不存在")
 	}
-	if req.Keyword != "" {
-		keyword := "%" + req.Keyword + "%"
-		query = query.Where("title LIKE ? OR content_raw LIKE ?", keyword, keyword)
+
+	var article model.Article
+	if err := l.svcCtx.DB.Select("id", "slug", "author_id", "status").First(&article, record.ArticleID).Error; err != nil {
+		return 0, "", errorx.NewNotFoundError("文章不存在")
+	}
+	userID, _ := l.ctx.Value("userId").(uint)
+	if !canViewArticle(l.svcCtx.DB, &article, userID) {
+		return 0, "", errorx.NewNotFoundError("文章不存在")
 	}
 
-	query.Count(&total)
+	return article.ID, article.Slug, nil
+}
 
+// List 文章列表。page 按页码分页，翻到较深的页时建议改用上一页返回的 nextCursor，
+// 按排序键定位，不受翻页期间新增、删除文章的影响
+func (l *ArticleLogic) List(req *types.ArticleListRequest) (*types.ArticleListResponse, error) {
 	if req.Page <= 0 {
 		req.Page = 1
 	}
 	if req.PageSize <= 0 {
 		req.PageSize = 10
 	}
+	if req.PageSize > maxArticlePageSize {
+		req.PageSize = maxArticlePageSize
+	}
+	if req.Sort == "" {
+		req.Sort = articleSortLatest
+	}
 
-	offset := (req.Page - 1) * req.PageSize
-	if err := query.Preload("Author").
-		Order("created_at DESC").
-		Offset(offset).
-		Limit(req.PageSize).
+	filter, err := newArticleFilter(req)
+	if err != nil {
+		return nil, err
+	}
+	query := filter.apply(l.svcCtx.DB, l.svcCtx.DB.Model(&model.Article{}))
+
+	var cursor *articleCursor
+	if req.Cursor != "" {
+		if cursor, err = decodeArticleCursor(req.Cursor, req.Sort); err != nil {
+			return nil, errorx.NewParamError("无效的游标")
+		}
+	}
+	withTotal := cursor == nil
+	if req.WithTotal != nil {
+		withTotal = *req.WithTotal
+	}
+
+	if req.Sort == articleSortTrending {
+		return l.listTrending(query, req, cursor, withTotal)
+	}
+
+	total := int64(-1)
+	if withTotal {
+		query.Count(&total)
+	}
+
+	// 各排序方式都以ID作为最后的排序键，保证顺序稳定，游标可以准确定位
+	var viewsSince string
+	switch req.Sort {
+	case articleSortLatest:
+		query = query.Order("created_at DESC, id DESC")
+		if cursor != nil {
+			query = keysetAfter(query, []string{"created_at", "id"}, "<", cursor.time(0), cursor.id)
+		}
+	case articleSortOldest:
+		query = query.Order("created_at ASC, id ASC")
+		if cursor != nil {
+			query = keysetAfter(query, []string{"created_at", "id"}, ">", cursor.time(0), cursor.id)
+		}
+	case articleSortUpdated:
+		query = query.Order("updated_at DESC, id DESC")
+		if cursor != nil {
+			query = keysetAfter(query, []string{"updated_at", "id"}, "<", cursor.time(0), cursor.id)
+		}
+	case articleSortLikes:
+		query = query.Order("like_count DESC, id DESC")
+		if cursor != nil {
+			query = keysetAfter(query, []string{"like_count", "id"}, "<", cursor.values[0], cursor.id)
+		}
+	case articleSortViews:
+		days := req.Days
+		if days <= 0 {
+			days = l.svcCtx.Config.Ranking.Window
+		}
+		if days > l.svcCtx.Config.Analytics.MaxRangeDays {
+			return nil, errorx.NewParamError("统计天数不能超过 " + strconv.Itoa(l.svcCtx.Config.Analytics.MaxRangeDays))
+		}
+		if cursor != nil && cursor.values[0] != int64(days) {
+			return nil, errorx.NewParamError("无效的游标")
+		}
+		viewsSince = l.svcCtx.Analytics.Day(time.Now().AddDate(0, 0, -(days - 1)))
+		views := l.svcCtx.DB.Model(&model.ArticleDailyStat{}).
+			Select("article_id, SUM(views) AS total").
+			Where("day >= ?", viewsSince).
+			Group("article_id")
+		query = query.Select("articles.*").
+			Joins("LEFT JOIN (?) AS ranked ON ranked.article_id = articles.id", views).
+			Order("COALESCE(ranked.total, 0) DESC, articles.view_count DESC, articles.id DESC")
+		if cursor != nil {
+			query = keysetAfter(query, []string{"COALESCE(ranked.total, 0)", "articles.view_count", "articles.id"}, "<",
+				cursor.values[1], cursor.values[2], cursor.id)
+		}
+	default:
+		return nil, errorx.NewParamError("不支持的排序方式")
+	}
+
+	// 多取一条判断是否还有下一页
+	if cursor == nil {
+		query = query.Offset((req.Page - 1) * req.PageSize)
+	}
+	var articles []model.Article
+	if err := query.Preload("Author").Preload("Tags").Preload("Categories").
+		Limit(req.PageSize + 1).
 		Find(&articles).Error; err != nil {
 		return nil, errorx.NewDefaultError("获取文章列表失败")
 	}
 
+	resp := &types.ArticleListResponse{
+		PageResponse: types.PageResponse{
+			Total:    total,
+			Page:     req.Page,
+			PageSize: req.PageSize,
+		},
+	}
+	if cursor != nil {
+		resp.Page = 0
+	}
+	if len(articles) > req.PageSize {
+		articles = articles[:req.PageSize]
+		resp.HasMore = true
+		resp.NextCursor = l.nextArticleCursor(req, &articles[len(articles)-1], viewsSince)
+	}
+
 	list := make([]*types.ArticleResponse, len(articles))
 	for i, article := range articles {
 		list[i] = l.articleToResponse(&article)
 	}
+	userID, _ := l.ctx.Value("userId").(uint)
+	fillViewerState(l.svcCtx.DB, userID, list...)
+	fillCoverSrcset(l.svcCtx.DB, l.svcCtx.Config.Site, list...)
+	fillCoAuthors(l.svcCtx.DB, list...)
+	resp.List = list
+
+	return resp, nil
+}
+
+// nextArticleCursor 由本页最后一篇文章的排序键生成下一页的游标
+func (l *ArticleLogic) nextArticleCursor(req *types.ArticleListRequest, last *model.Article, viewsSince string) string {
+	c := &articleCursor{sort: req.Sort, id: last.ID}
+	switch req.Sort {
+	case articleSortLatest, articleSortOldest:
+		c.values = []int64{last.CreatedAt.UnixNano()}
+	case articleSortUpdated:
+		c.values = []int64{last.UpdatedAt.UnixNano()}
+	case articleSortLikes:
+		c.values = []int64{last.LikeCount}
+	case articleSortViews:
+		// 统计天数不同时排序不同，一起记录在游标中
+		days := req.Days
+		if days <= 0 {
+			days = l.svcCtx.Config.Ranking.Window
+		}
+		var views int64
+		l.svcCtx.DB.Model(&model.ArticleDailyStat{}).
+			Select("COALESCE(SUM(views), 0)").
+			Where("article_id = ? AND day >= ?", last.ID, viewsSince).
+			Scan(&views)
+		c.values = []int64{int64(days), views, last.ViewCount}
+	}
+	return encodeArticleCursor(c)
+}
+
+// articleFilter 文章列表和批量操作共用的筛选条件，零值表示不筛选
+type articleFilter struct {
+	Status     *int8
+	Statuses   []int8
+	AuthorID   uint
+	Keyword    string
+	TagID      uint
+	Tag        string // 按标签名筛选
+	CategoryID uint
+	HasCover   *bool
+
+	// 时间范围，包含开始时间，不包含结束时间
+	CreatedFrom, CreatedTo     *time.Time
+	PublishedFrom, PublishedTo *time.Time
+}
 
-	return &types.ArticleListResponse{
+// newArticleFilter 从列表请求中解析筛选条件
+func newArticleFilter(req *types.ArticleListRequest) (articleFilter, error) {
+	f := articleFilter{
+		Status:     req.Status,
+		Statuses:   req.Statuses,
+		AuthorID:   req.AuthorID,
+		Keyword:    req.Keyword,
+		TagID:      req.TagID,
+		Tag:        req.Tag,
+		CategoryID: req.CategoryID,
+		HasCover:   req.HasCover,
+	}
+
+	var err error
+	if f.CreatedFrom, f.CreatedTo, err = parseTimeRange(req.CreatedFrom, req.CreatedTo); err != nil {
+		return f, errorx.NewParamError("创建时间格式错误")
+	}
+	if f.PublishedFrom, f.PublishedTo, err = parseTimeRange(req.PublishedFrom, req.PublishedTo); err != nil {
+		return f, errorx.NewParamError("发布时间格式错误")
+	}
+	return f, nil
+}
+
+func (f articleFilter) apply(db, query *gorm.DB) *gorm.DB {
+	if f.Status != nil {
+		query = query.Where("status = ?", *f.Status)
+	}
+	if len(f.Statuses) > 0 {
+		query = query.Where("status IN ?", f.Statuses)
+	}
+	if f.AuthorID > 0 {
+		query = query.Where("author_id = ?", f.AuthorID)
+	}
+	if f.Keyword != "" {
+		keyword := "%" + f.Keyword + "%"
+		query = query.Where("title LIKE ? OR content_raw LIKE ?", keyword, keyword)
+	}
+	if f.TagID > 0 {
+		query = query.Where("id IN (?)", db.Model(&model.ArticleTag{}).
+			Select("article_id").Where("tag_id = ?", f.TagID))
+	}
+	if f.Tag != "" {
+		query = query.Where("id IN (?)", db.Model(&model.ArticleTag{}).
+			Select("article_tags.article_id").
+			Joins("JOIN tags ON tags.id = article_tags.tag_id").
+			Where("tags.name = ?", f.Tag))
+	}
+	if f.CategoryID > 0 {
+		query = query.Where("id IN (?)", db.Model(&model.ArticleCategory{}).
+			Select("article_id").Where("category_id = ?", f.CategoryID))
+	}
+	if f.HasCover != nil {
+		if *f.HasCover {
+			query = query.Where("cover IS NOT NULL AND cover <> ''")
+		} else {
+			query = query.Where("cover IS NULL OR cover = ''")
+		}
+	}
+	if f.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *f.CreatedFrom)
+	}
+	if f.CreatedTo != nil {
+		query = query.Where("created_at < ?", *f.CreatedTo)
+	}
+	if f.PublishedFrom != nil {
+		query = query.Where("published_at >= ?", *f.PublishedFrom)
+	}
+	if f.PublishedTo != nil {
+		query = query.Where("published_at < ?", *f.PublishedTo)
+	}
+	return query
+}
+
+// parseTimeRange 解析时间范围，格式为日期或日期时间。
+// 返回的结束时间不包含在范围内：只有日期时为第二天零点，否则为该秒之后
+func parseTimeRange(from, to string) (*time.Time, *time.Time, error) {
+	var start, end *time.Time
+	if from != "" {
+		t, _, err := parseDateOrDateTime(from)
+		if err != nil {
+			return nil, nil, err
+		}
+		start = &t
+	}
+	if to != "" {
+		t, dateOnly, err := parseDateOrDateTime(to)
+		if err != nil {
+			return nil, nil, err
+		}
+		if dateOnly {
+			t = t.AddDate(0, 0, 1)
+		} else {
+			t = t.Add(time.Second)
+		}
+		end = &t
+	}
+	return start, end, nil
+}
+
+func parseDateOrDateTime(s string) (time.Time, bool, error) {
+	if t, err := utils.ParseDateTime(s); err == nil {
+		return t, false, nil
+	}
+	t, err := utils.ParseDate(s)
+	return t, true, err
+}
+
+// keysetAfter 按排序键定位到游标之后，columns 和 values 一一对应，最后一个为ID。
+// op 为 < 时对应降序，> 对应升序
+func keysetAfter(query *gorm.DB, columns []string, op string, values ...interface{}) *gorm.DB {
+	// (a, b, id) < (x, y, z) 展开为 a < x OR (a = x AND (b < y OR (b = y AND id < z)))
+	n := len(columns)
+	cond := columns[n-1] + " " + op + " ?"
+	args := []interface{}{values[n-1]}
+	for i := n - 2; i >= 0; i-- {
+		cond = columns[i] + " " + op + " ? OR (" + columns[i] + " = ? AND (" + cond + "))"
+		args = append([]interface{}{values[i], values[i]}, args...)
+	}
+	return query.Where(cond, args...)
+}
+
+// articleCursor 文章列表的游标：排序方式、上一页最后一篇文章的排序键和ID。
+// 时间类的排序键记为纳秒时间戳；热度排序按排名位置翻页，只记录位置
+type articleCursor struct {
+	sort   string
+	values []int64
+	id     uint
+}
+
+// articleCursorValues 各排序方式游标中排序键的个数
+var articleCursorValues = map[string]int{
+	articleSortLatest:   1,
+	articleSortOldest:   1,
+	articleSortUpdated:  1,
+	articleSortLikes:    1,
+	articleSortViews:    3, // 统计天数、统计期内浏览量、总浏览量
+	articleSortTrending: 1, // 下一页在排行中的位置
+}
+
+func (c *articleCursor) time(i int) time.Time {
+	return time.Unix(0, c.values[i])
+}
+
+func encodeArticleCursor(c *articleCursor) string {
+	parts := make([]string, 0, len(c.values)+2)
+	parts = append(parts, c.sort)
+	for _, v := range c.values {
+		parts = append(parts, strconv.FormatInt(v, 10))
+	}
+	parts = append(parts, strconv.FormatUint(uint64(c.id), 10))
+	return base64.RawURLEncoding.EncodeToString([]byte(strings.Join(parts, ":")))
+}
+
+// decodeArticleCursor 解析游标，排序方式与本次请求不一致时报错
+func decodeArticleCursor(s, sort string) (*articleCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(string(raw), ":")
+	if len(parts) < 2 || parts[0] != sort || len(parts) != articleCursorValues[sort]+2 {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+
+	c := &articleCursor{sort: sort, values: make([]int64, len(parts)-2)}
+	for i := range c.values {
+		if c.values[i], err = strconv.ParseInt(parts[i+1], 10, 64); err != nil {
+			return nil, err
+		}
+	}
+	id, err := strconv.ParseUint(parts[len(parts)-1], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	c.id = uint(id)
+	return c, nil
+}
+
+// Trending 热门文章，只包含已发布的文章
+func (l *ArticleLogic) Trending(req *types.ArticleListRequest) (*types.ArticleListResponse, error) {
+	status := model.ArticleStatusPublished
+	req.Status = &status
+	req.Statuses = nil
+	req.Sort = articleSortTrending
+	return l.List(req)
+}
+
+// listTrending 按热度排序：先取排行中符合筛选条件的文章，再按排名分页。
+// 排名随时变化，游标只记录下一页在排行中的位置
+func (l *ArticleLogic) listTrending(query *gorm.DB, req *types.ArticleListRequest, cursor *articleCursor, withTotal bool) (*types.ArticleListResponse, error) {
+	ranked, err := l.svcCtx.Ranking.Trending()
+	if err != nil {
+		l.Logger.Errorf("load trending error: %v", err)
+		return nil, errorx.NewDefaultError("获取文章列表失败")
+	}
+
+	var matched []uint
+	if len(ranked) > 0 {
+		if err := query.Where("id IN ?", ranked).Pluck("id", &matched).Error; err != nil {
+			l.Logger.Errorf("filter trending error: %v", err)
+			return nil, errorx.NewDefaultError("获取文章列表失败")
+		}
+	}
+	keep := make(map[uint]bool, len(matched))
+	for _, id := range matched {
+		keep[id] = true
+	}
+
+	start := (req.Page - 1) * req.PageSize
+	if cursor != nil {
+		start = int(cursor.values[0])
+	}
+
+	page := make([]uint, 0, req.PageSize)
+	offset := start
+	hasMore := false
+	for _, id := range ranked {
+		if !keep[id] {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		if len(page) == req.PageSize {
+			hasMore = true
+			break
+		}
+		page = append(page, id)
+	}
+
+	list := make([]*types.ArticleResponse, 0, len(page))
+	if len(page) > 0 {
+		var articles []model.Article
+		if err := l.svcCtx.DB.Preload("Author").Preload("Tags").Preload("Categories").
+			Where("id IN ?", page).
+			Find(&articles).Error; err != nil {
+			return nil, errorx.NewDefaultError("获取文章列表失败")
+		}
+		byID := make(map[uint]*model.Article, len(articles))
+		for i := range articles {
+			byID[articles[i].ID] = &articles[i]
+		}
+		for _, id := range page {
+			if article, ok := byID[id]; ok {
+				list = append(list, l.articleToResponse(article))
+			}
+		}
+	}
+	userID, _ := l.ctx.Value("userId").(uint)
+	fillViewerState(l.svcCtx.DB, userID, list...)
+	fillCoverSrcset(l.svcCtx.DB, l.svcCtx.Config.Site, list...)
+	fillCoAuthors(l.svcCtx.DB, list...)
+
+	// 筛选排行时已得到总数，不需要额外的查询；与其他排序一致，不要求统计时返回 -1
+	total := int64(-1)
+	if withTotal {
+		total = int64(len(matched))
+	}
+	resp := &types.ArticleListResponse{
 		PageResponse: types.PageResponse{
 			Total:    total,
 			Page:     req.Page,
 			PageSize: req.PageSize,
 			List:     list,
 		},
-	}, nil
+		HasMore: hasMore,
+	}
+	if cursor != nil {
+		resp.Page = 0
+	}
+	if hasMore {
+		resp.NextCursor = encodeArticleCursor(&articleCursor{
+			sort:   articleSortTrending,
+			values: []int64{int64(start + len(page))},
+		})
+	}
+	return resp, nil
 }
 
 // SaveDraft 保存草稿（实时自动保存）
 func (l *ArticleLogic) SaveDraft(req *types.SaveDraftRequest) (*types.SaveDraftResponse, error) {
 	userID, ok := l.ctx.Value("userId").(uint)
 	if !ok {
-		userID = 1 // 开发模式：默认用户ID
+		return nil, errorx.NewUnauthorizedError("未登录")
 	}
 
-	draft := model.ArticleDraft{
-		ArticleID: req.ArticleID,
-		UserID:    userID,
-		Title:     req.Title,
-		Content:   req.Content,
+	// 与并发的保存冲突时重试：已有文章的草稿改为覆盖对方刚创建的记录，默认名称顺延到下一个
+	var draft *model.ArticleDraft
+	var err error
+	for attempt := 0; attempt < draftSaveAttempts; attempt++ {
+		err = l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+			var err error
+			draft, err = saveDraft(tx, l.svcCtx.Config.Draft, userID, req)
+			return err
+		})
+		if !errors.Is(err, errDraftConflict) {
+			break
+		}
+	}
+	if errors.Is(err, errDraftConflict) {
+		return nil, errorx.NewParamError("草稿正在被其他请求保存，请稍后重试")
 	}
-
-	// 使用 Upsert（存在则更新，不存在则创建）
-	err := l.svcCtx.DB.Where("article_id = ? AND user_id = ?", req.ArticleID, userID).
-		Assign(model.ArticleDraft{
-			Title:   req.Title,
-			Content: req.Content,
-		}).
-		FirstOrCreate(&draft).Error
-
 	if err != nil {
+		if codeErr, ok := err.(*errorx.CodeError); ok {
+			return nil, codeErr
+		}
 		l.Logger.Errorf("save draft error: %v", err)
 		return nil, errorx.NewDefaultError("保存草稿失败")
 	}
 
 	return &types.SaveDraftResponse{
 		DraftID: draft.ID,
+		Name:    draft.Name,
 		SavedAt: time.Now().Format("2006-01-02 15:04:05"),
 	}, nil
 }
 
-// GetVersions 获取版本历史
+// GetVersions 获取版本历史，可见范围与文章相同
 func (l *ArticleLogic) GetVersions(articleID uint) ([]*types.ArticleVersionResponse, error) {
+	if _, err := l.viewableArticle(articleID); err != nil {
+		return nil, err
+	}
+
 	var versions []model.ArticleVersion
 	if err := l.svcCtx.DB.Where("article_id = ?", articleID).
-		Order("version DESC").
+		Order("version ASC").
 		Find(&versions).Error; err != nil {
 		return nil, errorx.NewDefaultError("获取版本历史失败")
 	}
 
+	// 增量版本需要从快照开始依次还原
+	contents, err := restoreVersionContents(versions)
+	if err != nil {
+		l.Logger.Errorf("restore versions of article %d error: %v", articleID, err)
+		return nil, errorx.NewDefaultError("获取版本历史失败")
+	}
+
+	editorIDs := make([]uint, 0, len(versions))
+	for _, v := range versions {
+		if v.EditorID > 0 {
+			editorIDs = append(editorIDs, v.EditorID)
+		}
+	}
+	editors := userDisplayNames(l.svcCtx.DB, editorIDs)
+
 	list := make([]*types.ArticleVersionResponse, len(versions))
 	for i, v := range versions {
-		list[i] = &types.ArticleVersionResponse{
-			ID:        v.ID,
-			ArticleID: v.ArticleID,
-			Title:     v.Title,
-			Content:   v.Content,
-			Version:   v.Version,
-			Remark:    v.Remark,
-			CreatedAt: v.CreatedAt.Format("2006-01-02 15:04:05"),
+		list[len(versions)-1-i] = &types.ArticleVersionResponse{
+			ID:               v.ID,
+			ArticleID:        v.ArticleID,
+			Title:            v.Title,
+			Content:          contents[i],
+			Version:          v.Version,
+			Remark:           v.Remark,
+			Cover:            v.Cover,
+			Summary:          v.Summary,
+			SummaryGenerated: v.SummaryGenerated,
+			Status:           v.Status,
+			RestoredFrom:     v.RestoredFrom,
+			EditorID:         v.EditorID,
+			EditorName:       editors[v.EditorID],
+			CreatedAt:        v.CreatedAt.Format("2006-01-02 15:04:05"),
 		}
 	}
 
 	return list, nil
 }
 
-// RestoreVersion 恢复到指定版本
+// RestoreVersion 恢复到指定版本，标题、内容、封面和摘要一并恢复，状态在审核流程允许时恢复。
+// 恢复前的内容照常保存为历史版本，并记录恢复来源。
 func (l *ArticleLogic) RestoreVersion(articleID, versionID uint) (*types.ArticleResponse, error) {
-	// 开发模式：不检查权限
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
 
 	var article model.Article
 	if err := l.svcCtx.DB.First(&article, articleID).Error; err != nil {
 		return nil, errorx.NewNotFoundError("文章不存在")
 	}
+	if !canEditArticle(l.svcCtx.DB, &article, userID) {
+		return nil, errorx.NewForbiddenError("无权编辑该文章")
+	}
 
+	// 版本必须属于该文章，不能把其他文章的历史复制过来
 	var version model.ArticleVersion
-	if err := l.svcCtx.DB.First(&version, versionID).Error; err != nil {
+	if err := l.svcCtx.DB.Where("id = ? AND article_id = ?", versionID, article.ID).
+		First(&version).Error; err != nil {
 		return nil, errorx.NewNotFoundError("版本不存在")
 	}
+	if err := materializeVersion(l.svcCtx.DB, &version); err != nil {
+		l.Logger.Errorf("restore version %d error: %v", versionID, err)
+		return nil, errorx.NewDefaultError("版本数据损坏")
+	}
 
-	// 更新文章为历史版本的内容
-	req := &types.UpdateArticleRequest{
-		ID:      articleID,
-		Title:   version.Title,
-		Content: version.Content,
-		Remark:  "恢复到版本 " + string(rune(version.Version)),
+	// 早期版本没有记录状态，保持当前状态
+	actors := articleActors(l.svcCtx.DB, &article, userID)
+	restoreStatus := false
+	if version.Status != nil && *version.Status != article.Status {
+		restoreStatus = checkTransition(l.svcCtx.Config.Workflow, article.Status, *version.Status, actors) == nil
 	}
 
-	return l.Update(req)
+	// 恢复内容与修改内容一样可能需要重新审核，未审核的内容不随状态恢复直接上线
+	contentChanged := version.Title != article.Title || version.Content != article.Content
+	editStatus, editComment := article.Status, ""
+	if contentChanged {
+		if restoreStatus && *version.Status == model.ArticleStatusPublished {
+			to, _, _ := statusAfterEdit(l.svcCtx.Config.Workflow, model.ArticleStatusPublished, actors)
+			restoreStatus = to == model.ArticleStatusPublished
+		}
+		if !restoreStatus {
+			to, comment, err := statusAfterEdit(l.svcCtx.Config.Workflow, article.Status, actors)
+			if err != nil {
+				return nil, err
+			}
+			editStatus, editComment = to, comment
+		}
+	}
+
+	remark := restoreRemark(version.Version)
+	err := l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		oldTitle := article.Title
+		if err := saveArticleVersion(tx, l.svcCtx.Config.Version, &article, ""); err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"title":             version.Title,
+			"content":           version.Content,
+			"content_raw":       version.Content,
+			"cover":             version.Cover,
+			"summary":           version.Summary,
+			"summary_generated": version.SummaryGenerated,
+			"version":           article.Version + 1,
+			"editor_id":         userID,
+			"restored_from":     version.Version,
+		}
+		// 该版本没有摘要时按其正文生成
+		if version.Summary == "" {
+			updates["summary"] = generateSummary(l.svcCtx.Config.Summary, version.Content)
+			updates["summary_generated"] = true
+		}
+		if err := tx.Model(&article).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		if restoreStatus {
+			if err := transitionArticle(tx, &article, *version.Status, userID, remark); err != nil {
+				return err
+			}
+		} else if editStatus != article.Status {
+			if err := transitionArticle(tx, &article, editStatus, userID, editComment); err != nil {
+				return err
+			}
+		}
+
+		// 标题变化时重新生成永久链接，旧链接保留用于跳转
+		if version.Title != oldTitle || article.Slug == "" {
+			slug, err := generateArticleSlug(tx, version.Title, article.ID)
+			if err != nil {
+				return err
+			}
+			if err := assignArticleSlug(tx, &article, slug); err != nil {
+				return err
+			}
+		}
+
+		return syncMediaReferences(tx, l.svcCtx.Config.Site, article.ID)
+	})
+	if err != nil {
+		if codeErr, ok := err.(*errorx.CodeError); ok {
+			return nil, codeErr
+		}
+		l.Logger.Errorf("restore article version error: %v", err)
+		return nil, errorx.NewDefaultError("恢复版本失败")
+	}
+
+	l.svcCtx.DB.Preload("Author").Preload("Tags").Preload("Categories").First(&article, articleID)
+	resp := l.articleToResponse(&article)
+	fillCoAuthors(l.svcCtx.DB, resp)
+	return resp, nil
 }
 
 // Delete 删除文章
 func (l *ArticleLogic) Delete(id uint) error {
-	// 开发模式：不检查权限
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return errorx.NewUnauthorizedError("未登录")
+	}
 
 	var article model.Article
 	if err := l.svcCtx.DB.First(&article, id).Error; err != nil {
 		return errorx.NewNotFoundError("文章不存在")
 	}
+	if !isAuthorOrAdmin(l.svcCtx.DB, &article, userID) {
+		return errorx.NewForbiddenError("无权删除该文章")
+	}
 
-	// 软删除
-	if err := l.svcCtx.DB.Delete(&article).Error; err != nil {
+	// 软删除，移入回收站
+	err := l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		return trashArticle(tx, &article)
+	})
+	if err != nil {
 		l.Logger.Errorf("delete article error: %v", err)
 		return errorx.NewDefaultError("删除文章失败")
 	}
@@ -283,18 +1091,29 @@ func (l *ArticleLogic) Delete(id uint) error {
 
 func (l *ArticleLogic) articleToResponse(article *model.Article) *types.ArticleResponse {
 	resp := &types.ArticleResponse{
-		ID:        article.ID,
-		Title:     article.Title,
-		Content:   article.Content,
-		Cover:     article.Cover,
-		Summary:   article.Summary,
-		AuthorID:  article.AuthorID,
-		Status:    article.Status,
-		Version:   article.Version,
-		ViewCount: article.ViewCount,
-		LikeCount: article.LikeCount,
-		CreatedAt: article.CreatedAt.Format("2006-01-02 15:04:05"),
-		UpdatedAt: article.UpdatedAt.Format("2006-01-02 15:04:05"),
+		ID:         article.ID,
+		Title:      article.Title,
+		Slug:       article.Slug,
+		Content:    article.Content,
+		Cover:      article.Cover,
+		Summary:    article.Summary,
+		AuthorID:   article.AuthorID,
+		Status:     article.Status,
+		Version:    article.Version,
+		ViewCount:  article.ViewCount,
+		LikeCount:  article.LikeCount,
+		CreatedAt:  article.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:  article.UpdatedAt.Format("2006-01-02 15:04:05"),
+		Tags:       tagsToResponse(article.Tags),
+		Categories: categoriesToResponse(article.Categories),
+		CoAuthors:  []*types.ArticleCoAuthor{},
+
+		SummaryGenerated: article.SummaryGenerated,
+		RestoredFrom:     article.RestoredFrom,
+
+		PublishAt:   formatOptionalTime(article.PublishAt),
+		UnpublishAt: formatOptionalTime(article.UnpublishAt),
+		PublishedAt: formatOptionalTime(article.PublishedAt),
 	}
 
 	if article.Author != nil {
@@ -306,3 +1125,8 @@ func (l *ArticleLogic) articleToResponse(article *model.Article) *types.ArticleR
 
 	return resp
 }
+
+// generateSummary 从正文的第一个有效段落生成摘要
+func generateSummary(cfg config.SummaryConfig, content string) string {
+	return richtext.Summary(richtext.Parse(content), cfg.Length, cfg.MinParagraph)
+}
@@ -0,0 +1,164 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/errorx"
+	"acupofcoffee/model"
+)
+
+func TestArticleWritesRequireLogin(t *testing.T) {
+	svcCtx := newTestServiceContext(t)
+	a, err := NewArticleLogic(userContext(1), svcCtx).Create(&types.CreateArticleRequest{Title: "Title", Content: "content"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := NewArticleLogic(userContext(1), svcCtx).Update(&types.UpdateArticleRequest{ID: a.ID, Content: "content v2"}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	var version model.ArticleVersion
+	if err := svcCtx.DB.Where("article_id = ?", a.ID).First(&version).Error; err != nil {
+		t.Fatalf("load version: %v", err)
+	}
+
+	anonymous := NewArticleLogic(context.Background(), svcCtx)
+	_, updateErr := anonymous.Update(&types.UpdateArticleRequest{ID: a.ID, Content: "anonymous"})
+	_, restoreErr := anonymous.RestoreVersion(a.ID, version.ID)
+	deleteErr := anonymous.Delete(a.ID)
+	for name, err := range map[string]error{"update": updateErr, "restore": restoreErr, "delete": deleteErr} {
+		var codeErr *errorx.CodeError
+		if !errors.As(err, &codeErr) || codeErr.Code != errorx.CodeUnauthorized {
+			t.Errorf("anonymous %s: got %v, want unauthorized", name, err)
+		}
+	}
+
+	var after model.Article
+	if err := svcCtx.DB.First(&after, a.ID).Error; err != nil {
+		t.Fatalf("article deleted: %v", err)
+	}
+	if after.Content != "content v2" {
+		t.Fatalf("content = %q, want content v2", after.Content)
+	}
+}
+
+func TestCreateScheduleRequiresPublishRight(t *testing.T) {
+	svcCtx := newTestServiceContext(t)
+	svcCtx.Config.Workflow.RequireReview = true
+	publishAt := time.Now().Add(time.Hour).Format("2006-01-02 15:04:05")
+
+	_, err := NewArticleLogic(context.Background(), svcCtx).Create(&types.CreateArticleRequest{Title: "Title", Content: "content", PublishAt: publishAt})
+	var codeErr *errorx.CodeError
+	if !errors.As(err, &codeErr) || codeErr.Code != errorx.CodeUnauthorized {
+		t.Fatalf("anonymous create: got %v, want unauthorized", err)
+	}
+
+	// 强制审核时作者不能通过定时发布绕过审核
+	_, err = NewArticleLogic(userContext(1), svcCtx).Create(&types.CreateArticleRequest{Title: "Title", Content: "content", PublishAt: publishAt})
+	if !errors.As(err, &codeErr) || codeErr.Code != errorx.CodeForbidden {
+		t.Fatalf("scheduled create under review: got %v, want forbidden", err)
+	}
+
+	var count int64
+	svcCtx.DB.Model(&model.Article{}).Count(&count)
+	if count != 0 {
+		t.Fatalf("%d articles created, want 0", count)
+	}
+}
+
+func TestUnpublishedArticleHiddenFromStrangers(t *testing.T) {
+	svcCtx := newTestServiceContext(t)
+	reviewer := model.User{Username: "reviewer", Password: "x", Email: "reviewer@example.com", Role: model.UserRoleReviewer}
+	if err := svcCtx.DB.Create(&reviewer).Error; err != nil {
+		t.Fatalf("create reviewer: %v", err)
+	}
+	a, err := NewArticleLogic(userContext(1), svcCtx).Create(&types.CreateArticleRequest{Title: "Draft", Content: "secret"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	for name, ctx := range map[string]context.Context{"anonymous": context.Background(), "stranger": userContext(reviewer.ID + 1)} {
+		l := NewArticleLogic(ctx, svcCtx)
+		var codeErr *errorx.CodeError
+		if _, err := l.Get(a.ID, nil); !errors.As(err, &codeErr) || codeErr.Code != errorx.CodeNotFound {
+			t.Errorf("%s get: got %v, want not found", name, err)
+		}
+		if _, err := l.GetVersions(a.ID); !errors.As(err, &codeErr) || codeErr.Code != errorx.CodeNotFound {
+			t.Errorf("%s versions: got %v, want not found", name, err)
+		}
+		if _, _, err := l.ResolveSlug(a.Slug); !errors.As(err, &codeErr) || codeErr.Code != errorx.CodeNotFound {
+			t.Errorf("%s slug: got %v, want not found", name, err)
+		}
+	}
+
+	for name, ctx := range map[string]context.Context{"author": userContext(1), "reviewer": userContext(reviewer.ID)} {
+		if _, err := NewArticleLogic(ctx, svcCtx).Get(a.ID, nil); err != nil {
+			t.Errorf("%s get: %v", name, err)
+		}
+	}
+}
+
+func TestEditUnderRequiredReview(t *testing.T) {
+	svcCtx := newTestServiceContext(t)
+	admin := model.User{Username: "admin", Password: "x", Email: "admin@example.com", Role: model.UserRoleAdmin}
+	if err := svcCtx.DB.Create(&admin).Error; err != nil {
+		t.Fatalf("create admin: %v", err)
+	}
+	author := NewArticleLogic(userContext(admin.ID+1), svcCtx)
+
+	published, err := author.Create(&types.CreateArticleRequest{Title: "Published", Content: "v1", Status: model.ArticleStatusPublished})
+	if err != nil {
+		t.Fatalf("create published: %v", err)
+	}
+	inReview, err := author.Create(&types.CreateArticleRequest{Title: "In review", Content: "v1", Status: model.ArticleStatusInReview})
+	if err != nil {
+		t.Fatalf("create in review: %v", err)
+	}
+	svcCtx.Config.Workflow.RequireReview = true
+
+	// 审核中的文章不能修改内容
+	_, err = author.Update(&types.UpdateArticleRequest{ID: inReview.ID, Content: "v2"})
+	var codeErr *errorx.CodeError
+	if !errors.As(err, &codeErr) || codeErr.Code != errorx.CodeParamError {
+		t.Fatalf("edit in review: got %v, want param error", err)
+	}
+
+	// 管理员修改已发布的文章直接生效，作者修改后回到审核中
+	resp, err := NewArticleLogic(userContext(admin.ID), svcCtx).Update(&types.UpdateArticleRequest{ID: published.ID, Content: "v2"})
+	if err != nil || resp.Status != model.ArticleStatusPublished {
+		t.Fatalf("admin edit published: status=%v err=%v", resp, err)
+	}
+	resp, err = author.Update(&types.UpdateArticleRequest{ID: published.ID, Content: "v3"})
+	if err != nil {
+		t.Fatalf("author edit published: %v", err)
+	}
+	if resp.Status != model.ArticleStatusInReview {
+		t.Fatalf("author edit published: status = %d, want in review", resp.Status)
+	}
+}
+
+func TestTrendingListHonorsWithTotal(t *testing.T) {
+	svcCtx := newTestServiceContext(t)
+	l := NewArticleLogic(context.Background(), svcCtx)
+
+	withTotal, withoutTotal := true, false
+	for _, tt := range []struct {
+		withTotal *bool
+		want      int64
+	}{
+		{withTotal: nil, want: 0},
+		{withTotal: &withTotal, want: 0},
+		{withTotal: &withoutTotal, want: -1},
+	} {
+		resp, err := l.List(&types.ArticleListRequest{Sort: articleSortTrending, Page: 1, PageSize: 10, WithTotal: tt.withTotal})
+		if err != nil {
+			t.Fatalf("list trending: %v", err)
+		}
+		if resp.Total != tt.want {
+			t.Errorf("withTotal=%v: total = %d, want %d", tt.withTotal != nil && *tt.withTotal, resp.Total, tt.want)
+		}
+	}
+}
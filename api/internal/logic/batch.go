@@ -0,0 +1,418 @@
+package logic
+
+import (
+	"context"
+	"strconv"
+
+	"acupofcoffee/api/internal/svc"
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/errorx"
+	"acupofcoffee/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+// 批量操作
+const (
+	BatchActionPublish      = "publish"
+	BatchActionArchive      = "archive"
+	BatchActionDelete       = "delete"
+	BatchActionRestore      = "restore"
+	BatchActionChangeAuthor = "change-author"
+	BatchActionAddTag       = "add-tag"
+)
+
+// 单篇文章的执行结果
+const (
+	batchResultOK      = "ok"
+	batchResultSkipped = "skipped" // 已是目标状态，无需修改
+	batchResultFailed  = "failed"
+	batchResultAborted = "aborted" // atomic 模式下因其他文章失败而未执行
+)
+
+type BatchLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewBatchLogic(ctx context.Context, svcCtx *svc.ServiceContext) *BatchLogic {
+	return &BatchLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// batchOp 校验后的批量操作参数
+type batchOp struct {
+	action   string
+	userID   uint
+	authorID uint
+	tags     []string
+}
+
+// Run 对多篇文章执行同一操作，逐篇检查权限。
+// 默认逐篇执行，失败的不影响其他文章；atomic 时先全部检查，任一篇失败则都不执行，执行时在同一事务中
+func (l *BatchLogic) Run(req *types.ArticleBatchRequest) (*types.ArticleBatchResponse, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+
+	op, err := l.parseOp(req, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	articles, results, err := l.loadTargets(req, op)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &types.ArticleBatchResponse{
+		Action:  req.Action,
+		DryRun:  req.DryRun,
+		Atomic:  req.Atomic,
+		Results: results,
+	}
+
+	switch {
+	case req.DryRun:
+		for i, article := range articles {
+			if results[i].Result == batchResultFailed {
+				continue
+			}
+			skipped, err := l.applyItem(l.svcCtx.DB, op, article, false)
+			l.setResult(results[i], skipped, err)
+		}
+	case req.Atomic:
+		l.runAtomic(op, articles, results)
+	default:
+		for i, article := range articles {
+			if results[i].Result == batchResultFailed {
+				continue
+			}
+			var skipped bool
+			err := l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+				var err error
+				skipped, err = l.applyItem(tx, op, article, true)
+				return err
+			})
+			l.setResult(results[i], skipped, err)
+		}
+	}
+
+	resp.Total = len(results)
+	for _, r := range results {
+		switch r.Result {
+		case batchResultOK:
+			resp.Succeeded++
+		case batchResultSkipped:
+			resp.Skipped++
+		case batchResultFailed:
+			resp.Failed++
+		}
+	}
+	return resp, nil
+}
+
+// runAtomic 先检查全部文章，都可以执行时在同一事务中执行
+func (l *BatchLogic) runAtomic(op *batchOp, articles []*model.Article, results []*types.ArticleBatchResult) {
+	failed := false
+	for i, article := range articles {
+		if results[i].Result == batchResultFailed {
+			failed = true
+			continue
+		}
+		skipped, err := l.applyItem(l.svcCtx.DB, op, article, false)
+		l.setResult(results[i], skipped, err)
+		failed = failed || err != nil
+	}
+
+	if !failed {
+		failedAt := -1
+		var itemErr error
+		err := l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+			for i, article := range articles {
+				if results[i].Result != batchResultOK {
+					continue
+				}
+				if _, err := l.applyItem(tx, op, article, true); err != nil {
+					failedAt, itemErr = i, err
+					return err
+				}
+			}
+			return nil
+		})
+		if err == nil {
+			return
+		}
+		if failedAt < 0 {
+			// 提交失败，不属于某一篇文章
+			l.Logger.Errorf("batch %s commit error: %v", op.action, err)
+			for _, r := range results {
+				if r.Result == batchResultOK {
+					l.setResult(r, false, errorx.NewDefaultError("操作失败"))
+				}
+			}
+			return
+		}
+		l.setResult(results[failedAt], false, itemErr)
+	}
+
+	for _, r := range results {
+		if r.Result == batchResultOK {
+			r.Result = batchResultAborted
+		}
+	}
+}
+
+// parseOp 校验操作类型和参数
+func (l *BatchLogic) parseOp(req *types.ArticleBatchRequest, userID uint) (*batchOp, error) {
+	op := &batchOp{action: req.Action, userID: userID}
+
+	switch req.Action {
+	case BatchActionPublish, BatchActionArchive, BatchActionDelete, BatchActionRestore:
+	case BatchActionChangeAuthor:
+		if req.AuthorID == 0 {
+			return nil, errorx.NewParamError("请指定新作者")
+		}
+		var user model.User
+		if err := l.svcCtx.DB.Select("id").First(&user, req.AuthorID).Error; err != nil {
+			return nil, errorx.NewParamError("新作者不存在")
+		}
+		op.authorID = req.AuthorID
+	case BatchActionAddTag:
+		seen := make(map[string]bool, len(req.Tags))
+		for _, raw := range req.Tags {
+			name, err := normalizeTagName(raw)
+			if err != nil {
+				return nil, err
+			}
+			if !seen[name] {
+				seen[name] = true
+				op.tags = append(op.tags, name)
+			}
+		}
+		if len(op.tags) == 0 {
+			return nil, errorx.NewParamError("请指定要添加的标签")
+		}
+		if len(op.tags) > maxArticleTags {
+			return nil, errorx.NewParamError("标签数量过多")
+		}
+	default:
+		return nil, errorx.NewParamError("不支持的批量操作")
+	}
+
+	return op, nil
+}
+
+// loadTargets 按ID或筛选条件读取要操作的文章，results 与 articles 一一对应。
+// 按ID指定时，不存在的文章直接记为失败
+func (l *BatchLogic) loadTargets(req *types.ArticleBatchRequest, op *batchOp) ([]*model.Article, []*types.ArticleBatchResult, error) {
+	if len(req.IDs) == 0 && req.Filter == nil {
+		return nil, nil, errorx.NewParamError("请指定文章ID或筛选条件")
+	}
+	if len(req.IDs) > 0 && req.Filter != nil {
+		return nil, nil, errorx.NewParamError("文章ID和筛选条件只能指定一个")
+	}
+
+	maxItems := l.svcCtx.Config.Batch.MaxItems
+	tooMany := errorx.NewParamError("单次最多操作 " + strconv.Itoa(maxItems) + " 篇文章，请缩小范围")
+
+	// 恢复操作针对回收站中的文章
+	query := l.svcCtx.DB.Model(&model.Article{})
+	notFound := "文章不存在"
+	if op.action == BatchActionRestore {
+		query = l.svcCtx.DB.Unscoped().Model(&model.Article{}).Where("deleted_at IS NOT NULL")
+		notFound = "回收站中没有该文章"
+	}
+
+	if req.Filter != nil {
+		f := req.Filter
+		query = articleFilter{
+			Status:     f.Status,
+			AuthorID:   f.AuthorID,
+			Keyword:    f.Keyword,
+			TagID:      f.TagID,
+			Tag:        f.Tag,
+			CategoryID: f.CategoryID,
+		}.apply(l.svcCtx.DB, query)
+
+		// 非管理员只能按条件匹配自己的文章，避免对他人的文章逐篇返回无权限
+		var user model.User
+		if l.svcCtx.DB.Select("id", "role").First(&user, op.userID).Error != nil || !user.IsAdmin() {
+			query = query.Where("author_id = ?", op.userID)
+		}
+
+		var articles []*model.Article
+		if err := query.Order("id").Limit(maxItems + 1).Find(&articles).Error; err != nil {
+			l.Logger.Errorf("load batch targets error: %v", err)
+			return nil, nil, errorx.NewDefaultError("获取文章失败")
+		}
+		if len(articles) > maxItems {
+			return nil, nil, tooMany
+		}
+
+		results := make([]*types.ArticleBatchResult, len(articles))
+		for i, article := range articles {
+			results[i] = &types.ArticleBatchResult{ID: article.ID, Title: article.Title}
+		}
+		return articles, results, nil
+	}
+
+	// 按传入顺序去重
+	ids := make([]uint, 0, len(req.IDs))
+	seen := make(map[uint]bool, len(req.IDs))
+	for _, id := range req.IDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > maxItems {
+		return nil, nil, tooMany
+	}
+
+	var found []*model.Article
+	if err := query.Where("id IN ?", ids).Find(&found).Error; err != nil {
+		l.Logger.Errorf("load batch targets error: %v", err)
+		return nil, nil, errorx.NewDefaultError("获取文章失败")
+	}
+	byID := make(map[uint]*model.Article, len(found))
+	for _, article := range found {
+		byID[article.ID] = article
+	}
+
+	articles := make([]*model.Article, len(ids))
+	results := make([]*types.ArticleBatchResult, len(ids))
+	for i, id := range ids {
+		results[i] = &types.ArticleBatchResult{ID: id}
+		article, ok := byID[id]
+		if !ok {
+			l.setResult(results[i], false, errorx.NewNotFoundError(notFound))
+			continue
+		}
+		articles[i] = article
+		results[i].Title = article.Title
+	}
+	return articles, results, nil
+}
+
+// applyItem 检查当前用户能否对文章执行操作，apply 为 true 时执行。
+// 文章已是目标状态时返回 skipped
+func (l *BatchLogic) applyItem(tx *gorm.DB, op *batchOp, article *model.Article, apply bool) (bool, error) {
+	switch op.action {
+	case BatchActionPublish, BatchActionArchive:
+		to, comment := model.ArticleStatusPublished, "批量发布"
+		if op.action == BatchActionArchive {
+			to, comment = model.ArticleStatusArchived, "批量归档"
+		}
+		if article.Status == to {
+			return true, nil
+		}
+		actors := articleActors(tx, article, op.userID)
+		if err := checkTransition(l.svcCtx.Config.Workflow, article.Status, to, actors); err != nil {
+			return false, err
+		}
+		if !apply {
+			return false, nil
+		}
+		return false, transitionArticle(tx, article, to, op.userID, comment)
+	}
+
+	if !isAuthorOrAdmin(tx, article, op.userID) {
+		return false, errorx.NewForbiddenError("无权操作该文章")
+	}
+
+	switch op.action {
+	case BatchActionDelete:
+		if !apply {
+			return false, nil
+		}
+		return false, trashArticle(tx, article)
+
+	case BatchActionRestore:
+		if !apply {
+			return false, nil
+		}
+		return false, restoreArticle(tx, article)
+
+	case BatchActionChangeAuthor:
+		// 转让文章只能由作者本人或管理员操作，共同作者不行
+		if article.AuthorID != op.userID && !isAdmin(tx, op.userID) {
+			return false, errorx.NewForbiddenError("无权转让该文章")
+		}
+		if article.AuthorID == op.authorID {
+			return true, nil
+		}
+		if !apply {
+			return false, nil
+		}
+		if err := tx.Model(article).Update("author_id", op.authorID).Error; err != nil {
+			return false, err
+		}
+		// 新作者原来是协作者的，不再保留协作者记录
+		if err := tx.Where("article_id = ? AND user_id = ?", article.ID, op.authorID).
+			Delete(&model.ArticleCollaborator{}).Error; err != nil {
+			return false, err
+		}
+		article.AuthorID = op.authorID
+		return false, nil
+
+	case BatchActionAddTag:
+		var existing []string
+		if err := tx.Model(&model.ArticleTag{}).
+			Joins("JOIN tags ON tags.id = article_tags.tag_id").
+			Where("article_tags.article_id = ?", article.ID).
+			Pluck("tags.name", &existing).Error; err != nil {
+			return false, err
+		}
+		has := make(map[string]bool, len(existing))
+		for _, name := range existing {
+			has[name] = true
+		}
+		var missing []string
+		for _, name := range op.tags {
+			if !has[name] {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) == 0 {
+			return true, nil
+		}
+		if len(existing)+len(missing) > maxArticleTags {
+			return false, errorx.NewParamError("标签数量过多")
+		}
+		if !apply {
+			return false, nil
+		}
+		tags, err := resolveTags(tx, missing)
+		if err != nil {
+			return false, err
+		}
+		return false, tx.Model(article).Association("Tags").Append(tags)
+	}
+
+	return false, errorx.NewParamError("不支持的批量操作")
+}
+
+// setResult 记录单篇文章的结果，非业务错误只返回通用提示
+func (l *BatchLogic) setResult(r *types.ArticleBatchResult, skipped bool, err error) {
+	switch {
+	case err == nil && skipped:
+		r.Result, r.Code, r.Message = batchResultSkipped, 0, ""
+	case err == nil:
+		r.Result, r.Code, r.Message = batchResultOK, 0, ""
+	default:
+		r.Result = batchResultFailed
+		if codeErr, ok := err.(*errorx.CodeError); ok {
+			r.Code, r.Message = codeErr.Code, codeErr.Msg
+			return
+		}
+		l.Logger.Errorf("batch article %d error: %v", r.ID, err)
+		r.Code, r.Message = errorx.CodeServerError, "操作失败"
+	}
+}
@@ -0,0 +1,161 @@
+package logic
+
+import (
+	"context"
+	"strings"
+
+	"acupofcoffee/api/internal/svc"
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/errorx"
+	"acupofcoffee/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type CategoryLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCategoryLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CategoryLogic {
+	return &CategoryLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// List 分类列表（附带已发布文章数）
+func (l *CategoryLogic) List() ([]*types.CategoryResponse, error) {
+	var rows []struct {
+		ID           uint
+		Name         string
+		Description  string
+		Sort         int
+		ArticleCount int64
+	}
+
+	if err := l.svcCtx.DB.Table("categories").
+		Select("categories.id, categories.name, categories.description, categories.sort, COUNT(articles.id) AS article_count").
+		Joins("LEFT JOIN article_categories ON article_categories.category_id = categories.id").
+		Joins("LEFT JOIN articles ON articles.id = article_categories.article_id AND articles.deleted_at IS NULL AND articles.status = ?", model.ArticleStatusPublished).
+		Where("categories.deleted_at IS NULL").
+		Group("categories.id, categories.name, categories.description, categories.sort").
+		Order("categories.sort ASC, categories.id ASC").
+		Scan(&rows).Error; err != nil {
+		l.Logger.Errorf("list categories error: %v", err)
+		return nil, errorx.NewDefaultError("获取分类列表失败")
+	}
+
+	list := make([]*types.CategoryResponse, len(rows))
+	for i, row := range rows {
+		list[i] = &types.CategoryResponse{
+			ID:           row.ID,
+			Name:         row.Name,
+			Description:  row.Description,
+			Sort:         row.Sort,
+			ArticleCount: row.ArticleCount,
+		}
+	}
+
+	return list, nil
+}
+
+// Create 创建分类
+func (l *CategoryLogic) Create(req *types.CreateCategoryRequest) (*types.CategoryResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errorx.NewParamError("分类名不能为空")
+	}
+
+	var count int64
+	l.svcCtx.DB.Model(&model.Category{}).Where("name = ?", name).Count(&count)
+	if count > 0 {
+		return nil, errorx.NewParamError("分类已存在")
+	}
+
+	category := model.Category{
+		Name:        name,
+		Description: req.Description,
+		Sort:        req.Sort,
+	}
+	if err := l.svcCtx.DB.Create(&category).Error; err != nil {
+		l.Logger.Errorf("create category error: %v", err)
+		return nil, errorx.NewDefaultError("创建分类失败")
+	}
+
+	return categoryToResponse(&category), nil
+}
+
+// Update 更新分类
+func (l *CategoryLogic) Update(req *types.UpdateCategoryRequest) (*types.CategoryResponse, error) {
+	var category model.Category
+	if err := l.svcCtx.DB.First(&category, req.ID).Error; err != nil {
+		return nil, errorx.NewNotFoundError("分类不存在")
+	}
+
+	updates := map[string]interface{}{}
+	if name := strings.TrimSpace(req.Name); name != "" && name != category.Name {
+		var count int64
+		l.svcCtx.DB.Model(&model.Category{}).Where("name = ? AND id <> ?", name, category.ID).Count(&count)
+		if count > 0 {
+			return nil, errorx.NewParamError("分类已存在")
+		}
+		updates["name"] = name
+	}
+	if req.Description != "" {
+		updates["description"] = req.Description
+	}
+	if req.Sort != nil {
+		updates["sort"] = *req.Sort
+	}
+
+	if len(updates) > 0 {
+		if err := l.svcCtx.DB.Model(&category).Updates(updates).Error; err != nil {
+			l.Logger.Errorf("update category error: %v", err)
+			return nil, errorx.NewDefaultError("更新分类失败")
+		}
+	}
+
+	return categoryToResponse(&category), nil
+}
+
+// Delete 删除分类（同时解除与文章的关联）
+func (l *CategoryLogic) Delete(id uint) error {
+	var category model.Category
+	if err := l.svcCtx.DB.First(&category, id).Error; err != nil {
+		return errorx.NewNotFoundError("分类不存在")
+	}
+
+	err := l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("category_id = ?", id).Delete(&model.ArticleCategory{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&category).Error
+	})
+	if err != nil {
+		l.Logger.Errorf("delete category error: %v", err)
+		return errorx.NewDefaultError("删除分类失败")
+	}
+
+	return nil
+}
+
+func categoryToResponse(category *model.Category) *types.CategoryResponse {
+	return &types.CategoryResponse{
+		ID:          category.ID,
+		Name:        category.Name,
+		Description: category.Description,
+		Sort:        category.Sort,
+	}
+}
+
+func categoriesToResponse(categories []model.Category) []*types.CategoryResponse {
+	list := make([]*types.CategoryResponse, len(categories))
+	for i := range categories {
+		list[i] = categoryToResponse(&categories[i])
+	}
+	return list
+}
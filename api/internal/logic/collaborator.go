@@ -0,0 +1,446 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"acupofcoffee/api/internal/svc"
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/errorx"
+	"acupofcoffee/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type CollaboratorLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCollaboratorLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CollaboratorLogic {
+	return &CollaboratorLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// List 文章的作者和全部协作者（含待接受的邀请），作者排在最前。
+// 作者、已加入的协作者和管理员可以查看
+func (l *CollaboratorLogic) List(articleID uint) ([]*types.CollaboratorResponse, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+
+	var article model.Article
+	if err := l.svcCtx.DB.Preload("Author").First(&article, articleID).Error; err != nil {
+		return nil, errorx.NewNotFoundError("文章不存在")
+	}
+	if articleRole(l.svcCtx.DB, &article, userID) == "" && !isAdmin(l.svcCtx.DB, userID) {
+		return nil, errorx.NewForbiddenError("无权查看该文章的协作者")
+	}
+
+	var collaborators []model.ArticleCollaborator
+	if err := l.svcCtx.DB.Preload("User").
+		Where("article_id = ?", article.ID).
+		Order("id").
+		Find(&collaborators).Error; err != nil {
+		l.Logger.Errorf("list collaborators error: %v", err)
+		return nil, errorx.NewDefaultError("获取协作者失败")
+	}
+
+	list := make([]*types.CollaboratorResponse, 0, len(collaborators)+1)
+	owner := &types.CollaboratorResponse{
+		UserID:    article.AuthorID,
+		Role:      model.CollaboratorOwner,
+		CreatedAt: article.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if article.Author != nil {
+		owner.Username = article.Author.Username
+		owner.Nickname = article.Author.Nickname
+		owner.Avatar = article.Author.Avatar
+	}
+	list = append(list, owner)
+
+	for i := range collaborators {
+		list = append(list, collaboratorToResponse(&collaborators[i]))
+	}
+	return list, nil
+}
+
+// Invite 按用户名或邮箱邀请协作者，对方接受后生效。作者、共同作者和管理员可以邀请
+func (l *CollaboratorLogic) Invite(req *types.InviteCollaboratorRequest) (*types.CollaboratorResponse, error) {
+	userID, article, err := l.manageableArticle(req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !validCollaboratorRole(req.Role) {
+		return nil, errorx.NewParamError("协作者角色错误")
+	}
+
+	username, email := strings.TrimSpace(req.Username), strings.TrimSpace(req.Email)
+	var invitee model.User
+	switch {
+	case username != "" && email != "":
+		return nil, errorx.NewParamError("用户名和邮箱只能填写一个")
+	case username != "":
+		err = l.svcCtx.DB.Where("username = ?", username).First(&invitee).Error
+	case email != "":
+		err = l.svcCtx.DB.Where("email = ?", email).First(&invitee).Error
+	default:
+		return nil, errorx.NewParamError("请填写用户名或邮箱")
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errorx.NewNotFoundError("用户不存在")
+	}
+	if err != nil {
+		l.Logger.Errorf("find invitee error: %v", err)
+		return nil, errorx.NewDefaultError("邀请协作者失败")
+	}
+	if invitee.ID == article.AuthorID {
+		return nil, errorx.NewParamError("不能邀请文章作者")
+	}
+
+	var count int64
+	l.svcCtx.DB.Model(&model.ArticleCollaborator{}).
+		Where("article_id = ? AND user_id = ?", article.ID, invitee.ID).
+		Count(&count)
+	if count > 0 {
+		return nil, errorx.NewParamError("该用户已是协作者或已被邀请")
+	}
+
+	collaborator := model.ArticleCollaborator{
+		ArticleID: article.ID,
+		UserID:    invitee.ID,
+		Role:      req.Role,
+		InvitedBy: userID,
+	}
+	if err := l.svcCtx.DB.Create(&collaborator).Error; err != nil {
+		l.Logger.Errorf("invite collaborator error: %v", err)
+		return nil, errorx.NewDefaultError("邀请协作者失败")
+	}
+	collaborator.User = &invitee
+
+	return collaboratorToResponse(&collaborator), nil
+}
+
+// UpdateRole 修改协作者的角色，待接受的邀请也可以修改
+func (l *CollaboratorLogic) UpdateRole(req *types.UpdateCollaboratorRequest) (*types.CollaboratorResponse, error) {
+	_, article, err := l.manageableArticle(req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !validCollaboratorRole(req.Role) {
+		return nil, errorx.NewParamError("协作者角色错误")
+	}
+	if req.UserID == article.AuthorID {
+		return nil, errorx.NewParamError("不能修改文章作者的角色")
+	}
+
+	var collaborator model.ArticleCollaborator
+	if err := l.svcCtx.DB.Preload("User").
+		Where("article_id = ? AND user_id = ?", article.ID, req.UserID).
+		First(&collaborator).Error; err != nil {
+		return nil, errorx.NewNotFoundError("协作者不存在")
+	}
+
+	if collaborator.Role != req.Role {
+		if err := l.svcCtx.DB.Model(&collaborator).Update("role", req.Role).Error; err != nil {
+			l.Logger.Errorf("update collaborator role error: %v", err)
+			return nil, errorx.NewDefaultError("修改协作者角色失败")
+		}
+		collaborator.Role = req.Role
+	}
+
+	return collaboratorToResponse(&collaborator), nil
+}
+
+// Remove 移除协作者或撤回邀请，协作者也可以自己退出
+func (l *CollaboratorLogic) Remove(req *types.CollaboratorRequest) error {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return errorx.NewUnauthorizedError("未登录")
+	}
+
+	var article model.Article
+	if err := l.svcCtx.DB.First(&article, req.ID).Error; err != nil {
+		return errorx.NewNotFoundError("文章不存在")
+	}
+	if req.UserID == article.AuthorID {
+		return errorx.NewParamError("不能移除文章作者")
+	}
+	if req.UserID != userID && !canManageCollaborators(l.svcCtx.DB, &article, userID) {
+		return errorx.NewForbiddenError("无权管理该文章的协作者")
+	}
+
+	result := l.svcCtx.DB.Where("article_id = ? AND user_id = ?", article.ID, req.UserID).
+		Delete(&model.ArticleCollaborator{})
+	if result.Error != nil {
+		l.Logger.Errorf("remove collaborator error: %v", result.Error)
+		return errorx.NewDefaultError("移除协作者失败")
+	}
+	if result.RowsAffected == 0 {
+		return errorx.NewNotFoundError("协作者不存在")
+	}
+
+	return nil
+}
+
+// Invitations 当前用户待接受的协作邀请，最近的在前
+func (l *CollaboratorLogic) Invitations() ([]*types.InvitationResponse, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+
+	// 文章已删除的邀请不再展示
+	var invitations []model.ArticleCollaborator
+	if err := l.svcCtx.DB.
+		Joins("JOIN articles ON articles.id = article_collaborators.article_id AND articles.deleted_at IS NULL").
+		Where("article_collaborators.user_id = ? AND article_collaborators.accepted_at IS NULL", userID).
+		Order("article_collaborators.id DESC").
+		Find(&invitations).Error; err != nil {
+		l.Logger.Errorf("list invitations error: %v", err)
+		return nil, errorx.NewDefaultError("获取邀请失败")
+	}
+
+	articleIDs := make([]uint, 0, len(invitations))
+	inviterIDs := make([]uint, 0, len(invitations))
+	for _, inv := range invitations {
+		articleIDs = append(articleIDs, inv.ArticleID)
+		inviterIDs = append(inviterIDs, inv.InvitedBy)
+	}
+	titles := make(map[uint]string, len(articleIDs))
+	if len(articleIDs) > 0 {
+		var articles []model.Article
+		l.svcCtx.DB.Select("id", "title").Where("id IN ?", articleIDs).Find(&articles)
+		for _, a := range articles {
+			titles[a.ID] = a.Title
+		}
+	}
+	names := userDisplayNames(l.svcCtx.DB, inviterIDs)
+
+	list := make([]*types.InvitationResponse, len(invitations))
+	for i, inv := range invitations {
+		list[i] = &types.InvitationResponse{
+			ID:           inv.ID,
+			ArticleID:    inv.ArticleID,
+			ArticleTitle: titles[inv.ArticleID],
+			Role:         inv.Role,
+			InvitedBy:    inv.InvitedBy,
+			InviterName:  names[inv.InvitedBy],
+			CreatedAt:    inv.CreatedAt.Format("2006-01-02 15:04:05"),
+		}
+	}
+	return list, nil
+}
+
+// Accept 接受协作邀请
+func (l *CollaboratorLogic) Accept(id uint) error {
+	invitation, err := l.pendingInvitation(id)
+	if err != nil {
+		return err
+	}
+
+	var count int64
+	l.svcCtx.DB.Model(&model.Article{}).Where("id = ?", invitation.ArticleID).Count(&count)
+	if count == 0 {
+		return errorx.NewNotFoundError("文章不存在")
+	}
+
+	if err := l.svcCtx.DB.Model(invitation).Update("accepted_at", time.Now()).Error; err != nil {
+		l.Logger.Errorf("accept invitation error: %v", err)
+		return errorx.NewDefaultError("接受邀请失败")
+	}
+	return nil
+}
+
+// Decline 拒绝协作邀请，邀请记录直接删除，之后可以重新邀请
+func (l *CollaboratorLogic) Decline(id uint) error {
+	invitation, err := l.pendingInvitation(id)
+	if err != nil {
+		return err
+	}
+
+	if err := l.svcCtx.DB.Delete(invitation).Error; err != nil {
+		l.Logger.Errorf("decline invitation error: %v", err)
+		return errorx.NewDefaultError("拒绝邀请失败")
+	}
+	return nil
+}
+
+// manageableArticle 读取文章并检查当前用户能否管理协作者
+func (l *CollaboratorLogic) manageableArticle(id uint) (uint, *model.Article, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return 0, nil, errorx.NewUnauthorizedError("未登录")
+	}
+
+	var article model.Article
+	if err := l.svcCtx.DB.First(&article, id).Error; err != nil {
+		return 0, nil, errorx.NewNotFoundError("文章不存在")
+	}
+	if !canManageCollaborators(l.svcCtx.DB, &article, userID) {
+		return 0, nil, errorx.NewForbiddenError("无权管理该文章的协作者")
+	}
+	return userID, &article, nil
+}
+
+// pendingInvitation 读取当前用户收到的、尚未接受的邀请
+func (l *CollaboratorLogic) pendingInvitation(id uint) (*model.ArticleCollaborator, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+
+	var invitation model.ArticleCollaborator
+	if err := l.svcCtx.DB.Where("id = ? AND user_id = ? AND accepted_at IS NULL", id, userID).
+		First(&invitation).Error; err != nil {
+		return nil, errorx.NewNotFoundError("邀请不存在")
+	}
+	return &invitation, nil
+}
+
+func validCollaboratorRole(role string) bool {
+	switch role {
+	case model.CollaboratorCoAuthor, model.CollaboratorEditor, model.CollaboratorViewer:
+		return true
+	}
+	return false
+}
+
+// articleRole 用户在文章中的角色：作者本人为 owner，已接受邀请的协作者为其角色，其他人为空
+func articleRole(db *gorm.DB, article *model.Article, userID uint) string {
+	if userID == 0 {
+		return ""
+	}
+	if userID == article.AuthorID {
+		return model.CollaboratorOwner
+	}
+
+	var collaborator model.ArticleCollaborator
+	if db.Select("role").
+		Where("article_id = ? AND user_id = ? AND accepted_at IS NOT NULL", article.ID, userID).
+		First(&collaborator).Error != nil {
+		return ""
+	}
+	return collaborator.Role
+}
+
+// isArticleAuthor 作者本人或共同作者
+func isArticleAuthor(db *gorm.DB, article *model.Article, userID uint) bool {
+	role := articleRole(db, article, userID)
+	return role == model.CollaboratorOwner || role == model.CollaboratorCoAuthor
+}
+
+// canEditArticle 作者、共同作者、编辑和管理员可以修改文章内容
+func canEditArticle(db *gorm.DB, article *model.Article, userID uint) bool {
+	switch articleRole(db, article, userID) {
+	case model.CollaboratorOwner, model.CollaboratorCoAuthor, model.CollaboratorEditor:
+		return true
+	}
+	return isAdmin(db, userID)
+}
+
+// canManageCollaborators 作者、共同作者和管理员可以邀请和移除协作者
+func canManageCollaborators(db *gorm.DB, article *model.Article, userID uint) bool {
+	return isAuthorOrAdmin(db, article, userID)
+}
+
+// canViewArticle 已发布的文章所有人可见，其余状态只有作者、协作者、审核员和管理员可见
+func canViewArticle(db *gorm.DB, article *model.Article, userID uint) bool {
+	if article.Status == model.ArticleStatusPublished {
+		return true
+	}
+	if userID == 0 {
+		return false
+	}
+	if articleRole(db, article, userID) != "" {
+		return true
+	}
+	var user model.User
+	return db.Select("id", "role").First(&user, userID).Error == nil && user.IsReviewer()
+}
+
+func isAdmin(db *gorm.DB, userID uint) bool {
+	if userID == 0 {
+		return false
+	}
+	var user model.User
+	return db.Select("id", "role").First(&user, userID).Error == nil && user.IsAdmin()
+}
+
+// fillCoAuthors 批量补充文章的共同作者，按加入的先后排列
+func fillCoAuthors(db *gorm.DB, list ...*types.ArticleResponse) {
+	ids := make([]uint, 0, len(list))
+	for _, item := range list {
+		item.CoAuthors = []*types.ArticleCoAuthor{}
+		ids = append(ids, item.ID)
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	var collaborators []model.ArticleCollaborator
+	db.Preload("User").
+		Where("article_id IN ? AND role = ? AND accepted_at IS NOT NULL", ids, model.CollaboratorCoAuthor).
+		Order("accepted_at, id").
+		Find(&collaborators)
+
+	byArticle := make(map[uint][]*types.ArticleCoAuthor, len(ids))
+	for _, c := range collaborators {
+		if c.User == nil {
+			continue
+		}
+		byArticle[c.ArticleID] = append(byArticle[c.ArticleID], &types.ArticleCoAuthor{
+			UserID: c.UserID,
+			Name:   userDisplayName(*c.User),
+			Avatar: c.User.Avatar,
+		})
+	}
+	for _, item := range list {
+		if coAuthors, ok := byArticle[item.ID]; ok {
+			item.CoAuthors = coAuthors
+		}
+	}
+}
+
+// userDisplayNames 按用户ID批量读取显示名称
+func userDisplayNames(db *gorm.DB, ids []uint) map[uint]string {
+	names := make(map[uint]string, len(ids))
+	if len(ids) == 0 {
+		return names
+	}
+
+	var users []model.User
+	db.Select("id", "username", "nickname").Where("id IN ?", ids).Find(&users)
+	for _, user := range users {
+		names[user.ID] = userDisplayName(user)
+	}
+	return names
+}
+
+func collaboratorToResponse(c *model.ArticleCollaborator) *types.CollaboratorResponse {
+	resp := &types.CollaboratorResponse{
+		ID:        c.ID,
+		UserID:    c.UserID,
+		Role:      c.Role,
+		Pending:   c.AcceptedAt == nil,
+		InvitedBy: c.InvitedBy,
+		CreatedAt: c.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if c.AcceptedAt != nil {
+		resp.AcceptedAt = c.AcceptedAt.Format("2006-01-02 15:04:05")
+	}
+	if c.User != nil {
+		resp.Username = c.User.Username
+		resp.Nickname = c.User.Nickname
+		resp.Avatar = c.User.Avatar
+	}
+	return resp
+}
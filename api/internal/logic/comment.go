@@ -0,0 +1,595 @@
+package logic
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"acupofcoffee/api/internal/svc"
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/errorx"
+	"acupofcoffee/common/markdown"
+	"acupofcoffee/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CommentLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCommentLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CommentLogic {
+	return &CommentLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// List 文章的评论楼层，置顶优先，其余按时间倒序，每层附带前几条回复
+func (l *CommentLogic) List(req *types.CommentListRequest) (*types.PageResponse, error) {
+	userID, _ := l.ctx.Value("userId").(uint)
+
+	var article model.Article
+	if err := l.svcCtx.DB.Select("id", "author_id", "status").First(&article, req.ID).Error; err != nil {
+		return nil, errorx.NewNotFoundError("文章不存在")
+	}
+	moderator := canModerateComments(l.svcCtx.DB, &article, userID)
+	if article.Status != model.ArticleStatusPublished && !moderator {
+		return nil, errorx.NewNotFoundError("文章不存在")
+	}
+
+	query := l.svcCtx.DB.Model(&model.Comment{}).Where("article_id = ? AND root_id = 0", article.ID)
+	if req.Status != nil {
+		if !moderator {
+			return nil, errorx.NewForbiddenError("无权按状态筛选评论")
+		}
+		query = query.Where("status = ?", *req.Status)
+	} else {
+		query = visibleComments(query, userID, moderator)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var roots []model.Comment
+	if err := query.Order("pinned DESC, created_at DESC, id DESC").
+		Scopes(model.Paginate(req.Page, req.PageSize)).
+		Find(&roots).Error; err != nil {
+		l.Logger.Errorf("list comments error: %v", err)
+		return nil, errorx.NewDefaultError("获取评论失败")
+	}
+
+	// 每层的回复数和预览
+	counts := make(map[uint]int64, len(roots))
+	previews := make(map[uint][]model.Comment, len(roots))
+	if len(roots) > 0 {
+		ids := make([]uint, len(roots))
+		for i, root := range roots {
+			ids[i] = root.ID
+		}
+
+		var rows []struct {
+			RootID uint
+			Total  int64
+		}
+		visibleComments(l.svcCtx.DB.Model(&model.Comment{}), userID, moderator).
+			Select("root_id, COUNT(*) AS total").
+			Where("root_id IN ?", ids).
+			Group("root_id").
+			Scan(&rows)
+		for _, row := range rows {
+			counts[row.RootID] = row.Total
+		}
+
+		for _, root := range roots {
+			if counts[root.ID] == 0 || l.svcCtx.Config.Comment.ReplyPreview <= 0 {
+				continue
+			}
+			var replies []model.Comment
+			visibleComments(l.svcCtx.DB.Model(&model.Comment{}), userID, moderator).
+				Where("root_id = ?", root.ID).
+				Order("created_at ASC, id ASC").
+				Limit(l.svcCtx.Config.Comment.ReplyPreview).
+				Find(&replies)
+			previews[root.ID] = replies
+		}
+	}
+
+	all := make([]model.Comment, 0, len(roots))
+	all = append(all, roots...)
+	for _, replies := range previews {
+		all = append(all, replies...)
+	}
+	responses := l.commentsToResponse(all, userID, moderator)
+
+	list := make([]*types.CommentResponse, len(roots))
+	for i, root := range roots {
+		item := responses[root.ID]
+		item.ReplyCount = counts[root.ID]
+		for _, reply := range previews[root.ID] {
+			item.Replies = append(item.Replies, responses[reply.ID])
+		}
+		list[i] = item
+	}
+
+	return &types.PageResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		List:     list,
+	}, nil
+}
+
+// ListReplies 某一楼层的全部回复，按时间正序分页
+func (l *CommentLogic) ListReplies(req *types.CommentRepliesRequest) (*types.PageResponse, error) {
+	userID, _ := l.ctx.Value("userId").(uint)
+
+	root, article, err := l.findComment(req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if root.RootID != 0 {
+		return nil, errorx.NewParamError("只能查看楼层的回复")
+	}
+	moderator := canModerateComments(l.svcCtx.DB, article, userID)
+	if !moderator && (article.Status != model.ArticleStatusPublished || !commentVisible(root, userID)) {
+		return nil, errorx.NewNotFoundError("评论不存在")
+	}
+
+	query := visibleComments(l.svcCtx.DB.Model(&model.Comment{}), userID, moderator).Where("root_id = ?", root.ID)
+
+	var total int64
+	query.Count(&total)
+
+	var replies []model.Comment
+	if err := query.Order("created_at ASC, id ASC").
+		Scopes(model.Paginate(req.Page, req.PageSize)).
+		Find(&replies).Error; err != nil {
+		l.Logger.Errorf("list replies error: %v", err)
+		return nil, errorx.NewDefaultError("获取回复失败")
+	}
+
+	responses := l.commentsToResponse(replies, userID, moderator)
+	list := make([]*types.CommentResponse, len(replies))
+	for i, reply := range replies {
+		list[i] = responses[reply.ID]
+	}
+
+	return &types.PageResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		List:     list,
+	}, nil
+}
+
+// Create 发表评论或回复
+func (l *CommentLogic) Create(req *types.CreateCommentRequest) (*types.CommentResponse, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+
+	body, err := l.checkBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var article model.Article
+	if err := l.svcCtx.DB.Select("id", "author_id", "status").
+		Where("status = ?", model.ArticleStatusPublished).
+		First(&article, req.ArticleID).Error; err != nil {
+		return nil, errorx.NewNotFoundError("文章不存在")
+	}
+
+	comment := model.Comment{
+		ArticleID: article.ID,
+		UserID:    userID,
+		Body:      body,
+		Status:    model.CommentStatusApproved,
+	}
+
+	if req.ParentID > 0 {
+		var parent model.Comment
+		if err := l.svcCtx.DB.Where("article_id = ? AND status = ? AND removed = ?",
+			article.ID, model.CommentStatusApproved, false).
+			First(&parent, req.ParentID).Error; err != nil {
+			return nil, errorx.NewNotFoundError("回复的评论不存在")
+		}
+		comment.ParentID = parent.ID
+		comment.RootID = parent.RootID
+		if comment.RootID == 0 {
+			comment.RootID = parent.ID
+		}
+	}
+
+	moderator := canModerateComments(l.svcCtx.DB, &article, userID)
+	if l.svcCtx.Config.Comment.RequireApproval && !moderator {
+		comment.Status = model.CommentStatusPending
+	}
+
+	if comment.BodyHTML, err = markdown.Render(body); err != nil {
+		l.Logger.Errorf("render comment error: %v", err)
+		return nil, errorx.NewDefaultError("评论失败")
+	}
+
+	err = l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&comment).Error; err != nil {
+			return err
+		}
+		if comment.Status == model.CommentStatusApproved {
+			return notifyComment(tx, &comment, article.AuthorID)
+		}
+		return nil
+	})
+	if err != nil {
+		l.Logger.Errorf("create comment error: %v", err)
+		return nil, errorx.NewDefaultError("评论失败")
+	}
+	if comment.Status == model.CommentStatusApproved {
+		l.svcCtx.Ranking.Comment(article.ID)
+	}
+
+	return l.commentsToResponse([]model.Comment{comment}, userID, moderator)[comment.ID], nil
+}
+
+// Update 编辑自己的评论，仅限发表后的编辑时限内
+func (l *CommentLogic) Update(req *types.UpdateCommentRequest) (*types.CommentResponse, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+
+	comment, article, err := l.findComment(req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.UserID != userID {
+		return nil, errorx.NewForbiddenError("只能编辑自己的评论")
+	}
+	if comment.Removed {
+		return nil, errorx.NewNotFoundError("评论不存在")
+	}
+	if time.Since(comment.CreatedAt) > l.svcCtx.Config.Comment.EditWindow {
+		return nil, errorx.NewForbiddenError("已超过可编辑时间")
+	}
+
+	body, err := l.checkBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	html, err := markdown.Render(body)
+	if err != nil {
+		l.Logger.Errorf("render comment error: %v", err)
+		return nil, errorx.NewDefaultError("编辑失败")
+	}
+
+	// 需要审核时，修改后的内容重新进入审核
+	moderator := canModerateComments(l.svcCtx.DB, article, userID)
+	status := comment.Status
+	if l.svcCtx.Config.Comment.RequireApproval && !moderator && status == model.CommentStatusApproved {
+		status = model.CommentStatusPending
+	}
+
+	now := time.Now()
+	if err := l.svcCtx.DB.Model(comment).Updates(map[string]interface{}{
+		"body":      body,
+		"body_html": html,
+		"status":    status,
+		"edited_at": now,
+	}).Error; err != nil {
+		l.Logger.Errorf("update comment error: %v", err)
+		return nil, errorx.NewDefaultError("编辑失败")
+	}
+	comment.Body, comment.BodyHTML, comment.Status, comment.EditedAt = body, html, status, &now
+
+	return l.commentsToResponse([]model.Comment{*comment}, userID, moderator)[comment.ID], nil
+}
+
+// Delete 删除评论：本人在删除时限内，或文章作者、管理员随时可删；
+// 仍有回复的评论只清空内容，保留楼层结构
+func (l *CommentLogic) Delete(id uint) error {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return errorx.NewUnauthorizedError("未登录")
+	}
+
+	comment, article, err := l.findComment(id)
+	if err != nil {
+		return err
+	}
+	if comment.Removed {
+		return nil
+	}
+
+	if !canModerateComments(l.svcCtx.DB, article, userID) {
+		if comment.UserID != userID {
+			return errorx.NewForbiddenError("无权删除该评论")
+		}
+		if time.Since(comment.CreatedAt) > l.svcCtx.Config.Comment.DeleteWindow {
+			return errorx.NewForbiddenError("已超过可删除时间")
+		}
+	}
+
+	err = l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		column := "parent_id"
+		if comment.RootID == 0 {
+			column = "root_id"
+		}
+		var replies int64
+		if err := tx.Model(&model.Comment{}).Where(column+" = ?", comment.ID).Count(&replies).Error; err != nil {
+			return err
+		}
+
+		if replies > 0 {
+			if err := tx.Model(comment).Updates(map[string]interface{}{
+				"body":      "",
+				"body_html": "",
+				"removed":   true,
+				"pinned":    false,
+			}).Error; err != nil {
+				return err
+			}
+		} else if err := tx.Delete(comment).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("comment_id = ?", comment.ID).Delete(&model.Notification{}).Error
+	})
+	if err != nil {
+		l.Logger.Errorf("delete comment error: %v", err)
+		return errorx.NewDefaultError("删除失败")
+	}
+
+	return nil
+}
+
+// Pin 置顶楼层，仅文章作者和管理员
+func (l *CommentLogic) Pin(id uint) (*types.CommentResponse, error) {
+	return l.setPinned(id, true)
+}
+
+// Unpin 取消置顶
+func (l *CommentLogic) Unpin(id uint) (*types.CommentResponse, error) {
+	return l.setPinned(id, false)
+}
+
+func (l *CommentLogic) setPinned(id uint, pinned bool) (*types.CommentResponse, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+
+	comment, article, err := l.findComment(id)
+	if err != nil {
+		return nil, err
+	}
+	if !canModerateComments(l.svcCtx.DB, article, userID) {
+		return nil, errorx.NewForbiddenError("只有文章作者或管理员可以置顶评论")
+	}
+	if pinned {
+		if comment.RootID != 0 {
+			return nil, errorx.NewParamError("只能置顶楼层，不能置顶回复")
+		}
+		if comment.Removed || comment.Status != model.CommentStatusApproved {
+			return nil, errorx.NewParamError("只能置顶已公开的评论")
+		}
+	}
+
+	if err := l.svcCtx.DB.Model(comment).Update("pinned", pinned).Error; err != nil {
+		l.Logger.Errorf("pin comment error: %v", err)
+		return nil, errorx.NewDefaultError("操作失败")
+	}
+	comment.Pinned = pinned
+
+	return l.commentsToResponse([]model.Comment{*comment}, userID, true)[comment.ID], nil
+}
+
+// SetStatus 审核评论，仅文章作者和管理员；首次通过时通知相关用户
+func (l *CommentLogic) SetStatus(req *types.CommentStatusRequest) (*types.CommentResponse, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+	if req.Status < model.CommentStatusPending || req.Status > model.CommentStatusHidden {
+		return nil, errorx.NewParamError("评论状态错误")
+	}
+
+	comment, article, err := l.findComment(req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !canModerateComments(l.svcCtx.DB, article, userID) {
+		return nil, errorx.NewForbiddenError("只有文章作者或管理员可以审核评论")
+	}
+
+	updates := map[string]interface{}{"status": req.Status}
+	if req.Status != model.CommentStatusApproved {
+		updates["pinned"] = false
+	}
+
+	err = l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(comment).Updates(updates).Error; err != nil {
+			return err
+		}
+		if req.Status == model.CommentStatusApproved && !comment.Removed {
+			return notifyComment(tx, comment, article.AuthorID)
+		}
+		return nil
+	})
+	if err != nil {
+		l.Logger.Errorf("set comment status error: %v", err)
+		return nil, errorx.NewDefaultError("操作失败")
+	}
+	if comment.Status == model.CommentStatusPending && req.Status == model.CommentStatusApproved && !comment.Removed {
+		l.svcCtx.Ranking.Comment(article.ID)
+	}
+	comment.Status = req.Status
+	if req.Status != model.CommentStatusApproved {
+		comment.Pinned = false
+	}
+
+	return l.commentsToResponse([]model.Comment{*comment}, userID, true)[comment.ID], nil
+}
+
+func (l *CommentLogic) findComment(id uint) (*model.Comment, *model.Article, error) {
+	var comment model.Comment
+	if err := l.svcCtx.DB.First(&comment, id).Error; err != nil {
+		return nil, nil, errorx.NewNotFoundError("评论不存在")
+	}
+
+	var article model.Article
+	if err := l.svcCtx.DB.Select("id", "author_id", "status").First(&article, comment.ArticleID).Error; err != nil {
+		return nil, nil, errorx.NewNotFoundError("评论不存在")
+	}
+
+	return &comment, &article, nil
+}
+
+func (l *CommentLogic) checkBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", errorx.NewParamError("评论内容不能为空")
+	}
+	if utf8.RuneCountInString(body) > l.svcCtx.Config.Comment.MaxLength {
+		return "", errorx.NewParamError("评论内容过长")
+	}
+	return body, nil
+}
+
+// commentsToResponse 批量转换评论，按评论ID索引
+func (l *CommentLogic) commentsToResponse(comments []model.Comment, userID uint, moderator bool) map[uint]*types.CommentResponse {
+	cfg := l.svcCtx.Config.Comment
+
+	// 被回复评论的作者，回复楼层本身时不需要显示
+	parentIDs := make([]uint, 0)
+	for _, c := range comments {
+		if c.ParentID != 0 && c.ParentID != c.RootID {
+			parentIDs = append(parentIDs, c.ParentID)
+		}
+	}
+	replyTo := make(map[uint]uint, len(parentIDs))
+	if len(parentIDs) > 0 {
+		var parents []model.Comment
+		l.svcCtx.DB.Unscoped().Select("id", "user_id").Where("id IN ?", parentIDs).Find(&parents)
+		for _, p := range parents {
+			replyTo[p.ID] = p.UserID
+		}
+	}
+
+	userIDs := make([]uint, 0, len(comments)+len(replyTo))
+	for _, c := range comments {
+		userIDs = append(userIDs, c.UserID)
+	}
+	for _, id := range replyTo {
+		userIDs = append(userIDs, id)
+	}
+	users := make(map[uint]model.User, len(userIDs))
+	if len(userIDs) > 0 {
+		var list []model.User
+		l.svcCtx.DB.Select("id", "username", "nickname", "avatar").Where("id IN ?", userIDs).Find(&list)
+		for _, u := range list {
+			users[u.ID] = u
+		}
+	}
+
+	result := make(map[uint]*types.CommentResponse, len(comments))
+	for _, c := range comments {
+		owner := c.UserID == userID
+		item := &types.CommentResponse{
+			ID:        c.ID,
+			ArticleID: c.ArticleID,
+			RootID:    c.RootID,
+			ParentID:  c.ParentID,
+			UserID:    c.UserID,
+			UserName:  userDisplayName(users[c.UserID]),
+			Avatar:    users[c.UserID].Avatar,
+			Body:      c.Body,
+			BodyHTML:  c.BodyHTML,
+			Status:    c.Status,
+			Pinned:    c.Pinned,
+			Removed:   c.Removed,
+			CanEdit:   owner && !c.Removed && time.Since(c.CreatedAt) <= cfg.EditWindow,
+			CanDelete: !c.Removed && (moderator || owner && time.Since(c.CreatedAt) <= cfg.DeleteWindow),
+			CreatedAt: c.CreatedAt.Format("2006-01-02 15:04:05"),
+		}
+		if id, ok := replyTo[c.ParentID]; ok {
+			item.ReplyToID = id
+			item.ReplyToName = userDisplayName(users[id])
+		}
+		if c.EditedAt != nil {
+			item.EditedAt = c.EditedAt.Format("2006-01-02 15:04:05")
+		}
+		result[c.ID] = item
+	}
+	return result
+}
+
+// canModerateComments 文章作者、共同作者和管理员可以审核、置顶和删除评论
+func canModerateComments(db *gorm.DB, article *model.Article, userID uint) bool {
+	return isAuthorOrAdmin(db, article, userID)
+}
+
+// visibleComments 普通读者只能看到已通过的评论和自己待审核的评论
+func visibleComments(db *gorm.DB, userID uint, moderator bool) *gorm.DB {
+	if moderator {
+		return db
+	}
+	if userID == 0 {
+		return db.Where("status = ?", model.CommentStatusApproved)
+	}
+	return db.Where("(status = ? OR (status = ? AND user_id = ?))",
+		model.CommentStatusApproved, model.CommentStatusPending, userID)
+}
+
+func commentVisible(comment *model.Comment, userID uint) bool {
+	return comment.Status == model.CommentStatusApproved ||
+		comment.Status == model.CommentStatusPending && userID != 0 && comment.UserID == userID
+}
+
+// notifyComment 评论公开后通知被回复的人和文章作者，重复调用不会重复通知
+func notifyComment(tx *gorm.DB, comment *model.Comment, articleAuthorID uint) error {
+	notes := make([]model.Notification, 0, 2)
+
+	if comment.ParentID > 0 {
+		var parent model.Comment
+		if tx.Select("id", "user_id").First(&parent, comment.ParentID).Error == nil && parent.UserID != comment.UserID {
+			notes = append(notes, model.Notification{
+				UserID:    parent.UserID,
+				Type:      model.NotificationReply,
+				CommentID: comment.ID,
+				ActorID:   comment.UserID,
+				ArticleID: comment.ArticleID,
+			})
+		}
+	}
+
+	// 作者已经作为被回复人收到通知时不再重复通知
+	if articleAuthorID != comment.UserID && (len(notes) == 0 || notes[0].UserID != articleAuthorID) {
+		notes = append(notes, model.Notification{
+			UserID:    articleAuthorID,
+			Type:      model.NotificationComment,
+			CommentID: comment.ID,
+			ActorID:   comment.UserID,
+			ArticleID: comment.ArticleID,
+		})
+	}
+
+	if len(notes) == 0 {
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&notes).Error
+}
+
+func userDisplayName(user model.User) string {
+	if user.Nickname != "" {
+		return user.Nickname
+	}
+	return user.Username
+}
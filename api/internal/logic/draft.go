@@ -0,0 +1,420 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"acupofcoffee/api/internal/config"
+	"acupofcoffee/api/internal/svc"
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/errorx"
+	"acupofcoffee/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	maxDraftNameLength = 100
+	defaultDraftName   = "未命名草稿"
+
+	// 并发保存撞上唯一索引时整体重试的次数
+	draftSaveAttempts = 3
+)
+
+// errDraftConflict 新建草稿时 (用户, 文章, 名称) 已被并发的保存占用
+var errDraftConflict = errors.New("draft conflict")
+
+type DraftLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewDraftLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DraftLogic {
+	return &DraftLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// List 当前用户的草稿，按最近编辑排序
+func (l *DraftLogic) List() ([]*types.DraftResponse, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+
+	var drafts []model.ArticleDraft
+	if err := l.svcCtx.DB.Select("id", "user_id", "article_id", "name", "title", "updated_at").
+		Where("user_id = ?", userID).
+		Order("updated_at DESC").
+		Find(&drafts).Error; err != nil {
+		l.Logger.Errorf("list drafts error: %v", err)
+		return nil, errorx.NewDefaultError("获取草稿列表失败")
+	}
+
+	// 已有文章的当前标题
+	ids := make([]uint, 0, len(drafts))
+	for _, d := range drafts {
+		if d.ArticleID > 0 {
+			ids = append(ids, d.ArticleID)
+		}
+	}
+	titles := make(map[uint]string, len(ids))
+	if len(ids) > 0 {
+		var articles []model.Article
+		l.svcCtx.DB.Select("id", "title").Where("id IN ?", ids).Find(&articles)
+		for _, a := range articles {
+			titles[a.ID] = a.Title
+		}
+	}
+
+	list := make([]*types.DraftResponse, len(drafts))
+	for i := range drafts {
+		list[i] = draftToResponse(&drafts[i], false)
+		list[i].ArticleTitle = titles[drafts[i].ArticleID]
+	}
+	return list, nil
+}
+
+// Get 获取草稿内容，用于继续编辑
+func (l *DraftLogic) Get(id uint) (*types.DraftResponse, error) {
+	draft, err := l.findOwnDraft(id)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := draftToResponse(draft, true)
+	if draft.ArticleID > 0 {
+		var article model.Article
+		if l.svcCtx.DB.Select("id", "title").First(&article, draft.ArticleID).Error == nil {
+			resp.ArticleTitle = article.Title
+		}
+	}
+	return resp, nil
+}
+
+// Delete 丢弃草稿
+func (l *DraftLogic) Delete(id uint) error {
+	draft, err := l.findOwnDraft(id)
+	if err != nil {
+		return err
+	}
+
+	err = l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		return purgeDrafts(tx, []uint{draft.ID})
+	})
+	if err != nil {
+		l.Logger.Errorf("delete draft error: %v", err)
+		return errorx.NewDefaultError("删除草稿失败")
+	}
+	return nil
+}
+
+// ListSnapshots 草稿的自动保存快照，按时间倒序
+func (l *DraftLogic) ListSnapshots(draftID uint) ([]*types.DraftSnapshotResponse, error) {
+	draft, err := l.findOwnDraft(draftID)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []model.DraftSnapshot
+	if err := l.svcCtx.DB.Select("id", "draft_id", "title", "size", "pinned", "created_at").
+		Where("draft_id = ?", draft.ID).
+		Order("id DESC").
+		Find(&snapshots).Error; err != nil {
+		l.Logger.Errorf("list draft snapshots error: %v", err)
+		return nil, errorx.NewDefaultError("获取草稿历史失败")
+	}
+
+	list := make([]*types.DraftSnapshotResponse, len(snapshots))
+	for i := range snapshots {
+		list[i] = snapshotToResponse(&snapshots[i], false)
+	}
+	return list, nil
+}
+
+// GetSnapshot 获取快照内容
+func (l *DraftLogic) GetSnapshot(req *types.DraftSnapshotRequest) (*types.DraftSnapshotResponse, error) {
+	snapshot, _, err := l.findSnapshot(req)
+	if err != nil {
+		return nil, err
+	}
+	return snapshotToResponse(snapshot, true), nil
+}
+
+// RestoreSnapshot 将草稿恢复为快照内容，恢复前的内容也会留存快照，可以再次撤销
+func (l *DraftLogic) RestoreSnapshot(req *types.DraftSnapshotRequest) (*types.DraftResponse, error) {
+	snapshot, draft, err := l.findSnapshot(req)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := l.svcCtx.Config.Draft
+	err = l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		if draft.Content != "" && draft.Content != snapshot.Content {
+			if err := addDraftSnapshot(tx, cfg, draft, false); err != nil {
+				return err
+			}
+		}
+
+		draft.Title = snapshot.Title
+		draft.Content = snapshot.Content
+		return tx.Model(draft).Updates(map[string]interface{}{
+			"title":   draft.Title,
+			"content": draft.Content,
+		}).Error
+	})
+	if err != nil {
+		l.Logger.Errorf("restore draft snapshot error: %v", err)
+		return nil, errorx.NewDefaultError("恢复草稿失败")
+	}
+
+	return draftToResponse(draft, true), nil
+}
+
+func (l *DraftLogic) findSnapshot(req *types.DraftSnapshotRequest) (*model.DraftSnapshot, *model.ArticleDraft, error) {
+	draft, err := l.findOwnDraft(req.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var snapshot model.DraftSnapshot
+	if err := l.svcCtx.DB.Where("id = ? AND draft_id = ?", req.SnapshotID, draft.ID).
+		First(&snapshot).Error; err != nil {
+		return nil, nil, errorx.NewNotFoundError("快照不存在")
+	}
+	return &snapshot, draft, nil
+}
+
+func (l *DraftLogic) findOwnDraft(id uint) (*model.ArticleDraft, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+
+	var draft model.ArticleDraft
+	if err := l.svcCtx.DB.Where("id = ? AND user_id = ?", id, userID).First(&draft).Error; err != nil {
+		return nil, errorx.NewNotFoundError("草稿不存在")
+	}
+	return &draft, nil
+}
+
+// saveDraft 保存草稿：已有文章按 (用户, 文章) 覆盖保存，需要编辑权限；新文章按 draftID 续写，不传时新建。
+// 覆盖前按配置为原内容留存快照。
+func saveDraft(tx *gorm.DB, cfg config.DraftConfig, userID uint, req *types.SaveDraftRequest) (*model.ArticleDraft, error) {
+	name := strings.TrimSpace(req.Name)
+	if len([]rune(name)) > maxDraftNameLength {
+		return nil, errorx.NewParamError("草稿名称过长")
+	}
+
+	var draft model.ArticleDraft
+	if req.ArticleID > 0 {
+		var article model.Article
+		if err := tx.Select("id", "author_id").First(&article, req.ArticleID).Error; err != nil {
+			return nil, errorx.NewNotFoundError("文章不存在")
+		}
+		if !canEditArticle(tx, &article, userID) {
+			return nil, errorx.NewForbiddenError("无权编辑该文章")
+		}
+
+		err := tx.Where("user_id = ? AND article_id = ?", userID, req.ArticleID).First(&draft).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			draft = model.ArticleDraft{UserID: userID, ArticleID: req.ArticleID}
+		} else if err != nil {
+			return nil, err
+		}
+	} else {
+		if req.DraftID > 0 {
+			if err := tx.Where("id = ? AND user_id = ? AND article_id = 0", req.DraftID, userID).
+				First(&draft).Error; err != nil {
+				return nil, errorx.NewNotFoundError("草稿不存在")
+			}
+		} else {
+			draft = model.ArticleDraft{UserID: userID}
+		}
+
+		if name != "" && name != draft.Name {
+			var count int64
+			tx.Model(&model.ArticleDraft{}).
+				Where("user_id = ? AND article_id = 0 AND name = ? AND id <> ?", userID, name, draft.ID).
+				Count(&count)
+			if count > 0 {
+				return nil, errorx.NewParamError("草稿名称已存在")
+			}
+			draft.Name = name
+		}
+		if draft.Name == "" {
+			generated, err := nextDraftName(tx, userID)
+			if err != nil {
+				return nil, err
+			}
+			draft.Name = generated
+		}
+	}
+
+	if err := snapshotDraft(tx, cfg, &draft, req.Content); err != nil {
+		return nil, err
+	}
+
+	draft.Title = req.Title
+	draft.Content = req.Content
+	if draft.ID > 0 {
+		return &draft, tx.Save(&draft).Error
+	}
+
+	// 名称是先查后写的，并发保存可能同时选中同一个名称，由唯一索引兜底
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&draft)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errDraftConflict
+	}
+	return &draft, nil
+}
+
+// snapshotDraft 在覆盖草稿前为原内容留存快照。
+// 距上一个快照不足 SnapshotInterval 时跳过；内容骤减时总是留存，并且与普通快照分开轮换。
+func snapshotDraft(tx *gorm.DB, cfg config.DraftConfig, draft *model.ArticleDraft, content string) error {
+	if draft.ID == 0 || draft.Content == "" || draft.Content == content {
+		return nil
+	}
+
+	oldSize := utf8.RuneCountInString(draft.Content)
+	newSize := utf8.RuneCountInString(content)
+	shrunk := cfg.ShrinkRatio > 0 && float64(oldSize-newSize) > float64(oldSize)*cfg.ShrinkRatio
+
+	if !shrunk {
+		var last model.DraftSnapshot
+		err := tx.Where("draft_id = ?", draft.ID).Order("id DESC").First(&last).Error
+		if err == nil && time.Since(last.CreatedAt) < cfg.SnapshotInterval {
+			return nil
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+	}
+
+	return addDraftSnapshot(tx, cfg, draft, shrunk)
+}
+
+// addDraftSnapshot 记录草稿当前内容，并淘汰同类中超出数量的旧快照
+func addDraftSnapshot(tx *gorm.DB, cfg config.DraftConfig, draft *model.ArticleDraft, pinned bool) error {
+	snapshot := model.DraftSnapshot{
+		DraftID: draft.ID,
+		Title:   draft.Title,
+		Content: draft.Content,
+		Size:    utf8.RuneCountInString(draft.Content),
+		Pinned:  pinned,
+	}
+	if err := tx.Create(&snapshot).Error; err != nil {
+		return err
+	}
+
+	// 反复删减又恢复的草稿会不断产生固定快照，同样只保留最近的若干个
+	limit := cfg.SnapshotLimit
+	if pinned {
+		limit = cfg.PinnedLimit
+	}
+	if limit <= 0 {
+		return nil
+	}
+	var expired []uint
+	if err := tx.Model(&model.DraftSnapshot{}).
+		Where("draft_id = ? AND pinned = ?", draft.ID, pinned).
+		Order("id DESC").
+		Offset(limit).
+		Pluck("id", &expired).Error; err != nil {
+		return err
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+	return tx.Delete(&model.DraftSnapshot{}, expired).Error
+}
+
+// nextDraftName 生成未被占用的默认草稿名：未命名草稿、未命名草稿 2 ...
+func nextDraftName(tx *gorm.DB, userID uint) (string, error) {
+	var names []string
+	if err := tx.Model(&model.ArticleDraft{}).
+		Where("user_id = ? AND article_id = 0 AND name LIKE ?", userID, defaultDraftName+"%").
+		Pluck("name", &names).Error; err != nil {
+		return "", err
+	}
+
+	taken := make(map[string]bool, len(names))
+	for _, n := range names {
+		taken[n] = true
+	}
+	for n := 1; ; n++ {
+		candidate := defaultDraftName
+		if n > 1 {
+			candidate += " " + strconv.Itoa(n)
+		}
+		if !taken[candidate] {
+			return candidate, nil
+		}
+	}
+}
+
+// deleteArticleDrafts 清理文章的草稿，userID 为 0 时清理所有用户的草稿
+func deleteArticleDrafts(tx *gorm.DB, articleID, userID uint) error {
+	query := tx.Model(&model.ArticleDraft{}).Where("article_id = ?", articleID)
+	if userID > 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	var ids []uint
+	if err := query.Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	return purgeDrafts(tx, ids)
+}
+
+// purgeDrafts 删除草稿及其快照
+func purgeDrafts(tx *gorm.DB, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := tx.Where("draft_id IN ?", ids).Delete(&model.DraftSnapshot{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Delete(&model.ArticleDraft{}, ids).Error
+}
+
+func draftToResponse(draft *model.ArticleDraft, withContent bool) *types.DraftResponse {
+	resp := &types.DraftResponse{
+		ID:        draft.ID,
+		ArticleID: draft.ArticleID,
+		Name:      draft.Name,
+		Title:     draft.Title,
+		UpdatedAt: draft.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if withContent {
+		resp.Content = draft.Content
+	}
+	return resp
+}
+
+func snapshotToResponse(snapshot *model.DraftSnapshot, withContent bool) *types.DraftSnapshotResponse {
+	resp := &types.DraftSnapshotResponse{
+		ID:        snapshot.ID,
+		DraftID:   snapshot.DraftID,
+		Title:     snapshot.Title,
+		Size:      snapshot.Size,
+		Pinned:    snapshot.Pinned,
+		CreatedAt: snapshot.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if withContent {
+		resp.Content = snapshot.Content
+	}
+	return resp
+}
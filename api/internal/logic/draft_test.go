@@ -0,0 +1,35 @@
+package logic
+
+import (
+	"strings"
+	"testing"
+
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/model"
+)
+
+func TestDraftPinnedSnapshotsRotate(t *testing.T) {
+	svcCtx := newTestServiceContext(t)
+	l := NewArticleLogic(userContext(1), svcCtx)
+
+	long, short := strings.Repeat("正文", 100), "短"
+	resp, err := l.SaveDraft(&types.SaveDraftRequest{Content: long})
+	if err != nil {
+		t.Fatalf("save draft: %v", err)
+	}
+
+	// 反复删减又恢复，每次删减都会留存一个固定快照
+	for i := 0; i < 2*svcCtx.Config.Draft.PinnedLimit; i++ {
+		for _, content := range []string{short, long} {
+			if _, err := l.SaveDraft(&types.SaveDraftRequest{DraftID: resp.DraftID, Content: content}); err != nil {
+				t.Fatalf("save draft: %v", err)
+			}
+		}
+	}
+
+	var pinned int64
+	svcCtx.DB.Model(&model.DraftSnapshot{}).Where("draft_id = ? AND pinned = ?", resp.DraftID, true).Count(&pinned)
+	if pinned != int64(svcCtx.Config.Draft.PinnedLimit) {
+		t.Fatalf("got %d pinned snapshots, want %d", pinned, svcCtx.Config.Draft.PinnedLimit)
+	}
+}
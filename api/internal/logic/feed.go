@@ -0,0 +1,167 @@
+package logic
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"acupofcoffee/api/internal/svc"
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/errorx"
+	"acupofcoffee/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type FeedLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewFeedLogic(ctx context.Context, svcCtx *svc.ServiceContext) *FeedLogic {
+	return &FeedLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// feedPage 缓存的一页动态，不含当前用户的点赞、收藏状态
+type feedPage struct {
+	list       []*types.ArticleResponse
+	nextCursor string
+}
+
+// Feed 关注的作者发布的文章，按首次发布时间倒序，使用游标翻页
+func (l *FeedLogic) Feed(req *types.FeedRequest) (*types.FeedResponse, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+
+	cfg := l.svcCtx.Config.Feed
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = cfg.PageSize
+	}
+	if pageSize > cfg.MaxPageSize {
+		pageSize = cfg.MaxPageSize
+	}
+
+	var before *feedCursor
+	if req.Cursor != "" {
+		c, err := decodeFeedCursor(req.Cursor)
+		if err != nil {
+			return nil, errorx.NewParamError("无效的游标")
+		}
+		before = c
+	}
+
+	followees, err := l.svcCtx.Feed.Followees(userID, func() ([]uint, error) {
+		var ids []uint
+		err := l.svcCtx.DB.Model(&model.Follow{}).
+			Where("follower_id = ?", userID).
+			Order("followee_id").
+			Pluck("followee_id", &ids)
+		return ids, err.Error
+	})
+	if err != nil {
+		l.Logger.Errorf("load followees error: %v", err)
+		return nil, errorx.NewDefaultError("获取动态失败")
+	}
+	if len(followees) == 0 {
+		return &types.FeedResponse{List: make([]*types.ArticleResponse, 0)}, nil
+	}
+
+	key := fmt.Sprintf("%d:%s:%s:%d", userID, svc.Fingerprint(followees), req.Cursor, pageSize)
+	v, err := l.svcCtx.Feed.Page(key, func() (any, error) {
+		return l.loadPage(followees, before, pageSize)
+	})
+	if err != nil {
+		l.Logger.Errorf("load feed error: %v", err)
+		return nil, errorx.NewDefaultError("获取动态失败")
+	}
+	page := v.(*feedPage)
+
+	// 缓存的结果会被并发读取，复制后再填充当前用户的状态
+	list := make([]*types.ArticleResponse, len(page.list))
+	for i, item := range page.list {
+		copied := *item
+		list[i] = &copied
+	}
+	fillViewerState(l.svcCtx.DB, userID, list...)
+
+	return &types.FeedResponse{
+		List:       list,
+		NextCursor: page.nextCursor,
+	}, nil
+}
+
+// loadPage 查询关注作者的已发布文章，多取一条判断是否还有下一页
+func (l *FeedLogic) loadPage(followees []uint, before *feedCursor, pageSize int) (*feedPage, error) {
+	query := l.svcCtx.DB.Model(&model.Article{}).
+		Where("author_id IN ? AND status = ? AND published_at IS NOT NULL", followees, model.ArticleStatusPublished)
+	if before != nil {
+		query = query.Where("published_at < ? OR (published_at = ? AND id < ?)",
+			before.publishedAt, before.publishedAt, before.id)
+	}
+
+	var articles []model.Article
+	if err := query.Preload("Author").Preload("Tags").Preload("Categories").
+		Order("published_at DESC, id DESC").
+		Limit(pageSize + 1).
+		Find(&articles).Error; err != nil {
+		return nil, err
+	}
+
+	page := &feedPage{}
+	if len(articles) > pageSize {
+		articles = articles[:pageSize]
+		last := articles[pageSize-1]
+		page.nextCursor = encodeFeedCursor(&feedCursor{publishedAt: *last.PublishedAt, id: last.ID})
+	}
+
+	articleLogic := NewArticleLogic(l.ctx, l.svcCtx)
+	page.list = make([]*types.ArticleResponse, len(articles))
+	for i := range articles {
+		page.list[i] = articleLogic.articleToResponse(&articles[i])
+	}
+	fillCoverSrcset(l.svcCtx.DB, l.svcCtx.Config.Site, page.list...)
+	fillCoAuthors(l.svcCtx.DB, page.list...)
+	return page, nil
+}
+
+// feedCursor 上一页最后一篇文章的发布时间和ID
+type feedCursor struct {
+	publishedAt time.Time
+	id          uint
+}
+
+func encodeFeedCursor(c *feedCursor) string {
+	raw := strconv.FormatInt(c.publishedAt.UnixNano(), 10) + ":" + strconv.FormatUint(uint64(c.id), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeFeedCursor(s string) (*feedCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	ts, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+	nanos, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &feedCursor{publishedAt: time.Unix(0, nanos), id: uint(n)}, nil
+}
@@ -0,0 +1,208 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	"acupofcoffee/api/internal/svc"
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/errorx"
+	"acupofcoffee/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FollowLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewFollowLogic(ctx context.Context, svcCtx *svc.ServiceContext) *FollowLogic {
+	return &FollowLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// Follow 关注用户，重复关注不报错
+func (l *FollowLogic) Follow(followeeID uint) (*types.FollowResponse, error) {
+	return l.setFollow(followeeID, true)
+}
+
+// Unfollow 取消关注
+func (l *FollowLogic) Unfollow(followeeID uint) (*types.FollowResponse, error) {
+	return l.setFollow(followeeID, false)
+}
+
+func (l *FollowLogic) setFollow(followeeID uint, follow bool) (*types.FollowResponse, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+	if followeeID == userID {
+		return nil, errorx.NewParamError("不能关注自己")
+	}
+
+	var followee model.User
+	if err := l.svcCtx.DB.Select("id", "status").First(&followee, followeeID).Error; err != nil {
+		return nil, errorx.NewNotFoundError("用户不存在")
+	}
+	// 被禁用的用户仍然可以被取消关注
+	if follow && !followee.IsActive() {
+		return nil, errorx.NewNotFoundError("用户不存在")
+	}
+
+	// 只有关系真正变化时才调整双方的计数，计数和关系在同一事务中更新
+	err := l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		var result *gorm.DB
+		delta := 1
+		if follow {
+			result = tx.Clauses(clause.OnConflict{DoNothing: true}).
+				Create(&model.Follow{FollowerID: userID, FolloweeID: followeeID})
+		} else {
+			result = tx.Where("follower_id = ? AND followee_id = ?", userID, followeeID).Delete(&model.Follow{})
+			delta = -1
+		}
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		if err := tx.Model(&model.User{}).Where("id = ?", followeeID).
+			UpdateColumn("follower_count", gorm.Expr("follower_count + ?", delta)).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.User{}).Where("id = ?", userID).
+			UpdateColumn("following_count", gorm.Expr("following_count + ?", delta)).Error
+	})
+	if err != nil {
+		l.Logger.Errorf("set follow error: %v", err)
+		return nil, errorx.NewDefaultError("操作失败")
+	}
+	l.svcCtx.Feed.InvalidateFollowees(userID)
+
+	var followerCount int64
+	l.svcCtx.DB.Model(&model.User{}).Where("id = ?", followeeID).Pluck("follower_count", &followerCount)
+
+	return &types.FollowResponse{
+		UserID:        followeeID,
+		Following:     follow,
+		FollowerCount: followerCount,
+	}, nil
+}
+
+// Profile 用户公开主页
+func (l *FollowLogic) Profile(id uint) (*types.UserProfileResponse, error) {
+	var user model.User
+	if err := l.svcCtx.DB.Where("status = ?", 1).First(&user, id).Error; err != nil {
+		return nil, errorx.NewNotFoundError("用户不存在")
+	}
+
+	resp := &types.UserProfileResponse{
+		ID:             user.ID,
+		Username:       user.Username,
+		Nickname:       user.Nickname,
+		Avatar:         user.Avatar,
+		FollowerCount:  user.FollowerCount,
+		FollowingCount: user.FollowingCount,
+		CreatedAt:      user.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+
+	l.svcCtx.DB.Model(&model.Article{}).
+		Where("author_id = ? AND status = ?", user.ID, model.ArticleStatusPublished).
+		Count(&resp.ArticleCount)
+
+	if userID, ok := l.ctx.Value("userId").(uint); ok && userID != user.ID {
+		var n int64
+		l.svcCtx.DB.Model(&model.Follow{}).
+			Where("follower_id = ? AND followee_id = ?", userID, user.ID).
+			Count(&n)
+		resp.FollowedByMe = n > 0
+	}
+
+	return resp, nil
+}
+
+// Followers 关注该用户的人，按关注时间倒序
+func (l *FollowLogic) Followers(req *types.FollowListRequest) (*types.PageResponse, error) {
+	return l.listFollows(req, "followee_id", "follower_id")
+}
+
+// Following 该用户关注的人，按关注时间倒序
+func (l *FollowLogic) Following(req *types.FollowListRequest) (*types.PageResponse, error) {
+	return l.listFollows(req, "follower_id", "followee_id")
+}
+
+// listFollows 按 column 筛选关注关系，列出 other 一侧的用户
+func (l *FollowLogic) listFollows(req *types.FollowListRequest, column, other string) (*types.PageResponse, error) {
+	var n int64
+	l.svcCtx.DB.Model(&model.User{}).Where("id = ? AND status = ?", req.ID, 1).Count(&n)
+	if n == 0 {
+		return nil, errorx.NewNotFoundError("用户不存在")
+	}
+
+	query := l.svcCtx.DB.Table("follows").
+		Joins("JOIN users ON users.id = follows."+other+" AND users.deleted_at IS NULL AND users.status = ?", 1).
+		Where("follows."+column+" = ?", req.ID)
+
+	var total int64
+	query.Count(&total)
+
+	var rows []struct {
+		ID        uint
+		Username  string
+		Nickname  string
+		Avatar    string
+		CreatedAt time.Time
+	}
+	if err := query.Select("users.id, users.username, users.nickname, users.avatar, follows.created_at").
+		Order("follows.created_at DESC, follows.id DESC").
+		Scopes(model.Paginate(req.Page, req.PageSize)).
+		Scan(&rows).Error; err != nil {
+		l.Logger.Errorf("list follows error: %v", err)
+		return nil, errorx.NewDefaultError("获取关注列表失败")
+	}
+
+	list := make([]*types.FollowUserItem, len(rows))
+	for i, row := range rows {
+		list[i] = &types.FollowUserItem{
+			ID:         row.ID,
+			Username:   row.Username,
+			Nickname:   row.Nickname,
+			Avatar:     row.Avatar,
+			FollowedAt: row.CreatedAt.Format("2006-01-02 15:04:05"),
+		}
+	}
+
+	return &types.PageResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		List:     list,
+	}, nil
+}
+
+// ReconcileCounters 按关注记录校正用户的粉丝数和关注数，由后台任务周期调用
+func (l *FollowLogic) ReconcileCounters() error {
+	var corrected int64
+	for _, c := range []struct{ counter, column string }{
+		{"follower_count", "followee_id"},
+		{"following_count", "follower_id"},
+	} {
+		count := "SELECT COUNT(*) FROM follows WHERE follows." + c.column + " = users.id"
+		result := l.svcCtx.DB.Exec("UPDATE users SET " + c.counter + " = (" + count + ") WHERE " +
+			c.counter + " <> (" + count + ")")
+		if result.Error != nil {
+			return result.Error
+		}
+		corrected += result.RowsAffected
+	}
+
+	if corrected > 0 {
+		l.Logger.Infof("reconcile follow counters: %d users corrected", corrected)
+	}
+	return nil
+}
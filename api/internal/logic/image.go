@@ -0,0 +1,305 @@
+package logic
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"acupofcoffee/api/internal/config"
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/imaging"
+	"acupofcoffee/model"
+
+	"gorm.io/gorm"
+)
+
+// 刚上传的图片已直接放入队列，超过该时间仍未处理时才由定时任务重新排队
+const imageRequeueDelay = time.Minute
+
+// 变体可能的扩展名，删除时不依赖数据库记录也能找到全部对象；早期带透明通道的变体为 PNG
+var variantExtensions = []string{".jpg", ".webp", ".png"}
+
+type imageVariantSpec struct {
+	name  string
+	width int
+}
+
+// ProcessImage 生成图片的各尺寸变体，由图片处理队列调用。
+// 相同内容的其他记录已处理完成时直接复用其变体，不再重复生成
+func (l *MediaLogic) ProcessImage(id uint) error {
+	// 先改为处理中，多个实例或重复排队时只有一处会处理
+	result := l.svcCtx.DB.Model(&model.Media{}).
+		Where("id = ? AND variant_status = ?", id, model.MediaVariantPending).
+		Update("variant_status", model.MediaVariantProcessing)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	var media model.Media
+	if err := l.svcCtx.DB.First(&media, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	variants, ok, err := l.sharedVariants(&media)
+	if err == nil && !ok {
+		variants, err = l.generateVariants(&media)
+	}
+	if err != nil {
+		l.svcCtx.DB.Model(&media).Update("variant_status", model.MediaVariantFailed)
+		return err
+	}
+
+	return l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		// 处理期间文件已被删除时不再保存
+		result := tx.Model(&model.Media{}).
+			Where("id = ? AND variant_status = ?", media.ID, model.MediaVariantProcessing).
+			Update("variant_status", model.MediaVariantReady)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		if err := tx.Where("media_id = ?", media.ID).Delete(&model.MediaVariant{}).Error; err != nil {
+			return err
+		}
+		if len(variants) == 0 {
+			return nil
+		}
+		return tx.Create(&variants).Error
+	})
+}
+
+// RequeueImages 重新排队未处理的图片：队列已满或实例重启时丢失的任务，以及处理超时仍未完成的任务
+func (l *MediaLogic) RequeueImages() error {
+	cfg := l.svcCtx.Config.Media.Image
+	now := time.Now()
+
+	// 处理中的状态超过两倍处理超时仍未更新，说明处理的实例已退出。
+	// 不更新修改时间，以便下面直接重新排队
+	if err := l.svcCtx.DB.Model(&model.Media{}).
+		Where("variant_status = ? AND updated_at < ?", model.MediaVariantProcessing, now.Add(-2*cfg.Timeout)).
+		UpdateColumn("variant_status", model.MediaVariantPending).Error; err != nil {
+		return err
+	}
+
+	var ids []uint
+	if err := l.svcCtx.DB.Model(&model.Media{}).
+		Where("variant_status = ? AND updated_at < ?", model.MediaVariantPending, now.Add(-imageRequeueDelay)).
+		Order("id").
+		Limit(cfg.QueueSize).
+		Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+
+	for i, id := range ids {
+		if !l.svcCtx.Images.Enqueue(id) {
+			l.Logger.Infof("image queue is full, %d images left for next run", len(ids)-i)
+			break
+		}
+	}
+	return nil
+}
+
+// sharedVariants 复制相同内容的其他记录已生成的变体
+func (l *MediaLogic) sharedVariants(media *model.Media) ([]model.MediaVariant, bool, error) {
+	var source model.Media
+	err := l.svcCtx.DB.Select("id").
+		Where("storage_key = ? AND variant_status = ? AND id <> ?", media.StorageKey, model.MediaVariantReady, media.ID).
+		First(&source).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var variants []model.MediaVariant
+	if err := l.svcCtx.DB.Where("media_id = ?", source.ID).Find(&variants).Error; err != nil {
+		return nil, false, err
+	}
+	for i := range variants {
+		variants[i].ID = 0
+		variants[i].MediaID = media.ID
+		variants[i].CreatedAt = time.Time{}
+	}
+	return variants, true, nil
+}
+
+// generateVariants 解码原图并按方向校正，缩放到比原图窄的各个宽度后写入存储
+func (l *MediaLogic) generateVariants(media *model.Media) ([]model.MediaVariant, error) {
+	cfg := l.svcCtx.Config.Media.Image
+
+	r, err := l.svcCtx.Storage.Get(l.ctx, media.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(r)
+	r.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := imaging.Load(data, cfg.MaxPixels)
+	if err != nil {
+		return nil, err
+	}
+	width := img.Bounds().Dx()
+
+	var variants []model.MediaVariant
+	for _, spec := range imageVariantSpecs(cfg) {
+		if spec.width >= width {
+			continue
+		}
+		if err := l.ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		resized := imaging.Resize(img, spec.width)
+		var buf bytes.Buffer
+		contentType, err := imaging.Encode(&buf, resized, cfg.Quality)
+		if err != nil {
+			return nil, err
+		}
+
+		key := variantStorageKey(media.StorageKey, spec.name, contentType)
+		if err := l.svcCtx.Storage.Put(l.ctx, key, bytes.NewReader(buf.Bytes()), int64(buf.Len()), contentType); err != nil {
+			return nil, err
+		}
+		variants = append(variants, model.MediaVariant{
+			MediaID:     media.ID,
+			Name:        spec.name,
+			StorageKey:  key,
+			URL:         l.svcCtx.Storage.URL(key),
+			ContentType: contentType,
+			Size:        int64(buf.Len()),
+			Width:       resized.Bounds().Dx(),
+			Height:      resized.Bounds().Dy(),
+		})
+	}
+	return variants, nil
+}
+
+func imageVariantSpecs(cfg config.ImageConfig) []imageVariantSpec {
+	specs := make([]imageVariantSpec, 0, 3)
+	for _, spec := range []imageVariantSpec{
+		{name: model.MediaVariantThumbnail, width: cfg.Thumbnail},
+		{name: model.MediaVariantMedium, width: cfg.Medium},
+		{name: model.MediaVariantLarge, width: cfg.Large},
+	} {
+		if spec.width > 0 {
+			specs = append(specs, spec)
+		}
+	}
+	return specs
+}
+
+// variantStorageKey 在原图的 key 后加上变体名称，如 media/ab/<hash>-thumbnail.jpg
+func variantStorageKey(key, name, contentType string) string {
+	return strings.TrimSuffix(key, path.Ext(key)) + "-" + name + mediaExtensions[contentType]
+}
+
+// variantStorageKeys 原图所有可能的变体 key
+func variantStorageKeys(key string) []string {
+	base := strings.TrimSuffix(key, path.Ext(key))
+	keys := make([]string, 0, 3*len(variantExtensions))
+	for _, name := range []string{model.MediaVariantThumbnail, model.MediaVariantMedium, model.MediaVariantLarge} {
+		for _, ext := range variantExtensions {
+			keys = append(keys, base+"-"+name+ext)
+		}
+	}
+	return keys
+}
+
+// mediaVariants 按文件ID批量读取变体，按宽度从小到大排列
+func mediaVariants(db *gorm.DB, ids []uint) map[uint][]model.MediaVariant {
+	byMedia := make(map[uint][]model.MediaVariant, len(ids))
+	if len(ids) == 0 {
+		return byMedia
+	}
+
+	var variants []model.MediaVariant
+	db.Where("media_id IN ?", ids).Order("width").Find(&variants)
+	for _, v := range variants {
+		byMedia[v.MediaID] = append(byMedia[v.MediaID], v)
+	}
+	return byMedia
+}
+
+// mediaSrcset 由变体和原图组成 srcset，没有变体时返回空。prefix 用于补全站内的相对地址
+func mediaSrcset(url string, width int, variants []model.MediaVariant, prefix string) string {
+	if len(variants) == 0 {
+		return ""
+	}
+
+	resolve := func(u string) string {
+		if prefix != "" && strings.HasPrefix(u, "/") && !strings.HasPrefix(u, "//") {
+			return prefix + u
+		}
+		return u
+	}
+
+	sorted := append([]model.MediaVariant(nil), variants...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Width < sorted[j].Width })
+
+	parts := make([]string, 0, len(sorted)+1)
+	for _, v := range sorted {
+		parts = append(parts, resolve(v.URL)+" "+strconv.Itoa(v.Width)+"w")
+	}
+	if width > 0 {
+		parts = append(parts, resolve(url)+" "+strconv.Itoa(width)+"w")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// fillCoverSrcset 封面为已生成变体的上传图片时，补充封面的 srcset
+func fillCoverSrcset(db *gorm.DB, site config.SiteConfig, list ...*types.ArticleResponse) {
+	urls := make([]string, 0, len(list))
+	for _, item := range list {
+		if item.Cover != "" {
+			urls = append(urls, normalizeMediaURL(site, item.Cover))
+		}
+	}
+	if len(urls) == 0 {
+		return
+	}
+
+	var media []model.Media
+	db.Select("id", "url", "width").
+		Where("url IN ? AND variant_status = ?", urls, model.MediaVariantReady).
+		Order("id").
+		Find(&media)
+
+	// 相同内容的多条记录共用同一组变体，每个地址取一条即可
+	byURL := make(map[string]model.Media, len(media))
+	ids := make([]uint, 0, len(media))
+	for _, m := range media {
+		if _, ok := byURL[m.URL]; !ok {
+			byURL[m.URL] = m
+			ids = append(ids, m.ID)
+		}
+	}
+	variants := mediaVariants(db, ids)
+
+	for _, item := range list {
+		u := normalizeMediaURL(site, item.Cover)
+		m, ok := byURL[u]
+		if !ok {
+			continue
+		}
+		// 封面填写的是完整地址时，变体地址也补全为完整地址
+		prefix := ""
+		if u != item.Cover {
+			prefix = siteURL(site)
+		}
+		item.CoverSrcset = mediaSrcset(m.URL, m.Width, variants[m.ID], prefix)
+	}
+}
@@ -0,0 +1,52 @@
+package logic
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"acupofcoffee/api/internal/config"
+	"acupofcoffee/api/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/conf"
+)
+
+// newTestServiceContext 使用临时目录中的 SQLite 数据库和本地存储
+func newTestServiceContext(t *testing.T) *svc.ServiceContext {
+	t.Helper()
+
+	dir := t.TempDir()
+	yaml := `Name: test
+Port: 0
+MySQL:
+  DataSource: "sqlite:` + filepath.Join(dir, "test.db") + `"
+  MaxIdleConns: 1
+  MaxOpenConns: 1
+Redis:
+  Host: localhost:6379
+  Type: node
+Auth:
+  AccessSecret: test
+  AccessExpire: 3600
+Media:
+  Local:
+    Root: ` + filepath.Join(dir, "uploads") + `
+`
+	var c config.Config
+	if err := conf.LoadFromYamlBytes([]byte(yaml), &c); err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+
+	ctx := svc.NewServiceContext(c)
+	t.Cleanup(func() {
+		if db, err := ctx.DB.DB(); err == nil {
+			db.Close()
+		}
+	})
+	return ctx
+}
+
+// userContext 模拟已登录用户的请求上下文
+func userContext(userID uint) context.Context {
+	return context.WithValue(context.Background(), "userId", userID)
+}
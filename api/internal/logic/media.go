@@ -0,0 +1,457 @@
+package logic
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"image"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"acupofcoffee/api/internal/config"
+	"acupofcoffee/api/internal/svc"
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/errorx"
+	"acupofcoffee/common/imaging"
+	"acupofcoffee/common/richtext"
+	"acupofcoffee/common/storage"
+	"acupofcoffee/common/utils"
+	"acupofcoffee/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 未配置 AllowedTypes 时允许的类型
+var defaultMediaTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+
+// 常见类型使用固定的扩展名，mime 包对同一类型可能返回多个
+var mediaExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+	"video/mp4":       ".mp4",
+}
+
+type MediaLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewMediaLogic(ctx context.Context, svcCtx *svc.ServiceContext) *MediaLogic {
+	return &MediaLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// Upload 保存上传的文件，类型按内容识别而不是扩展名。
+// 同一用户重复上传相同内容时返回已有记录，不同用户上传相同内容时共用存储中的对象。
+// 图片去掉元数据后保存，并放入处理队列在后台生成各尺寸的变体
+func (l *MediaLogic) Upload(file io.ReadSeeker, filename string) (*types.MediaResponse, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+	cfg := l.svcCtx.Config.Media
+
+	h := sha256.New()
+	size, err := io.Copy(h, io.LimitReader(file, cfg.MaxSize+1))
+	if err != nil {
+		l.Logger.Errorf("read upload error: %v", err)
+		return nil, errorx.NewParamError("读取文件失败")
+	}
+	if size == 0 {
+		return nil, errorx.NewParamError("文件为空")
+	}
+	if size > cfg.MaxSize {
+		return nil, errorx.NewParamError("文件不能超过 " + formatBytes(cfg.MaxSize))
+	}
+	hash := hex.EncodeToString(h.Sum(nil))
+
+	contentType, err := sniffContentType(file)
+	if err != nil {
+		l.Logger.Errorf("read upload error: %v", err)
+		return nil, errorx.NewParamError("读取文件失败")
+	}
+	if !mediaTypeAllowed(cfg, contentType) {
+		return nil, errorx.NewParamError("不支持的文件类型: " + contentType)
+	}
+
+	var existing model.Media
+	if err := l.svcCtx.DB.Where("owner_id = ? AND hash = ?", userID, hash).First(&existing).Error; err == nil {
+		return l.toResponse(&existing, mediaVariants(l.svcCtx.DB, []uint{existing.ID})[existing.ID]), nil
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, errorx.NewParamError("读取文件失败")
+	}
+	body := file
+	orientation := 1
+	if strings.HasPrefix(contentType, "image/") {
+		// EXIF 等元数据可能包含拍摄地点和设备信息，保存前去掉，只保留方向
+		data, err := io.ReadAll(file)
+		if err != nil {
+			return nil, errorx.NewParamError("读取文件失败")
+		}
+		data = imaging.StripMetadata(contentType, data)
+		orientation = imaging.Orientation(data)
+		body, size = bytes.NewReader(data), int64(len(data))
+	}
+
+	media := model.Media{
+		OwnerID:     userID,
+		Hash:        hash,
+		Filename:    utils.TruncateRunes(filepath.Base(filepath.Clean("/"+filename)), 255),
+		ContentType: contentType,
+		Size:        size,
+	}
+	if c, _, err := image.DecodeConfig(body); err == nil {
+		media.Width, media.Height = imaging.DisplaySize(c.Width, c.Height, orientation)
+		media.VariantStatus = model.MediaVariantPending
+	}
+
+	// 其他用户已上传过相同内容时直接复用存储中的对象
+	var shared model.Media
+	if err := l.svcCtx.DB.Where("hash = ?", hash).First(&shared).Error; err == nil {
+		media.StorageKey, media.URL, media.Size = shared.StorageKey, shared.URL, shared.Size
+	} else {
+		media.StorageKey = mediaStorageKey(hash, contentType)
+		media.URL = l.svcCtx.Storage.URL(media.StorageKey)
+		if _, err := body.Seek(0, io.SeekStart); err != nil {
+			return nil, errorx.NewParamError("读取文件失败")
+		}
+		if err := l.svcCtx.Storage.Put(l.ctx, media.StorageKey, body, size, contentType); err != nil {
+			l.Logger.Errorf("store media error: %v", err)
+			return nil, errorx.NewDefaultError("保存文件失败")
+		}
+	}
+
+	// 并发上传同一文件时以先写入的记录为准
+	result := l.svcCtx.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&media)
+	if result.Error != nil {
+		l.Logger.Errorf("create media error: %v", result.Error)
+		return nil, errorx.NewDefaultError("保存文件失败")
+	}
+	if result.RowsAffected == 0 {
+		if err := l.svcCtx.DB.Where("owner_id = ? AND hash = ?", userID, hash).First(&media).Error; err != nil {
+			return nil, errorx.NewDefaultError("保存文件失败")
+		}
+		return l.toResponse(&media, mediaVariants(l.svcCtx.DB, []uint{media.ID})[media.ID]), nil
+	}
+
+	// 队列已满时保持待处理状态，由定时任务稍后重新排队
+	if media.VariantStatus == model.MediaVariantPending {
+		l.svcCtx.Images.Enqueue(media.ID)
+	}
+
+	return l.toResponse(&media, nil), nil
+}
+
+// List 当前用户上传的文件，按上传时间倒序
+func (l *MediaLogic) List(req *types.PageRequest) (*types.PageResponse, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+
+	query := l.svcCtx.DB.Model(&model.Media{}).Where("owner_id = ?", userID)
+
+	var total int64
+	query.Count(&total)
+
+	var media []model.Media
+	if err := query.Order("created_at DESC, id DESC").
+		Scopes(model.Paginate(req.Page, req.PageSize)).
+		Find(&media).Error; err != nil {
+		l.Logger.Errorf("list media error: %v", err)
+		return nil, errorx.NewDefaultError("获取文件列表失败")
+	}
+
+	ids := make([]uint, len(media))
+	for i := range media {
+		ids[i] = media[i].ID
+	}
+	refs := mediaRefCounts(l.svcCtx.DB, ids)
+	variants := mediaVariants(l.svcCtx.DB, ids)
+
+	list := make([]*types.MediaResponse, len(media))
+	for i := range media {
+		list[i] = l.toResponse(&media[i], variants[media[i].ID])
+		list[i].RefCount = refs[media[i].ID]
+	}
+
+	return &types.PageResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		List:     list,
+	}, nil
+}
+
+// Delete 删除文件，仍被文章引用时不允许删除；存储中的对象在没有其他记录使用时一并删除
+func (l *MediaLogic) Delete(id uint) error {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return errorx.NewUnauthorizedError("未登录")
+	}
+
+	var media model.Media
+	if err := l.svcCtx.DB.First(&media, id).Error; err != nil {
+		return errorx.NewNotFoundError("文件不存在")
+	}
+	if media.OwnerID != userID {
+		var user model.User
+		if l.svcCtx.DB.Select("id", "role").First(&user, userID).Error != nil || !user.IsAdmin() {
+			return errorx.NewForbiddenError("无权删除该文件")
+		}
+	}
+
+	if n := mediaRefCounts(l.svcCtx.DB, []uint{media.ID})[media.ID]; n > 0 {
+		return errorx.NewParamError("文件正在被 " + strconv.FormatInt(n, 10) + " 篇文章使用，无法删除")
+	}
+
+	orphaned := false
+	err := l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("media_id = ?", media.ID).Delete(&model.MediaReference{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("media_id = ?", media.ID).Delete(&model.MediaVariant{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&media).Error; err != nil {
+			return err
+		}
+
+		var others int64
+		if err := tx.Model(&model.Media{}).Where("storage_key = ?", media.StorageKey).Count(&others).Error; err != nil {
+			return err
+		}
+		orphaned = others == 0
+		return nil
+	})
+	if err != nil {
+		l.Logger.Errorf("delete media error: %v", err)
+		return errorx.NewDefaultError("删除文件失败")
+	}
+
+	// 对象删除失败只会留下无人引用的文件，不影响记录的删除。
+	// 变体按所有可能的 key 删除，处理中途被删除的图片也不会留下文件
+	if orphaned {
+		keys := []string{media.StorageKey}
+		if media.VariantStatus != "" {
+			keys = append(keys, variantStorageKeys(media.StorageKey)...)
+		}
+		for _, key := range keys {
+			if err := l.svcCtx.Storage.Delete(l.ctx, key); err != nil {
+				l.Logger.Errorf("delete media object %s error: %v", key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Open 读取存储中的对象，供本地存储通过本服务下载
+func (l *MediaLogic) Open(req *types.MediaFileRequest) (io.ReadCloser, string, error) {
+	key := "media/" + req.Shard + "/" + req.Name
+	if len(req.Shard) != 2 || !strings.HasPrefix(req.Name, req.Shard) || strings.ContainsAny(req.Name, "/\\") {
+		return nil, "", errorx.NewNotFoundError("文件不存在")
+	}
+
+	r, err := l.svcCtx.Storage.Get(l.ctx, key)
+	if err == storage.ErrNotFound {
+		return nil, "", errorx.NewNotFoundError("文件不存在")
+	}
+	if err != nil {
+		l.Logger.Errorf("open media %s error: %v", key, err)
+		return nil, "", errorx.NewDefaultError("读取文件失败")
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(req.Name))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return r, contentType, nil
+}
+
+func (l *MediaLogic) toResponse(media *model.Media, variants []model.MediaVariant) *types.MediaResponse {
+	resp := &types.MediaResponse{
+		ID:          media.ID,
+		URL:         media.URL,
+		Filename:    media.Filename,
+		ContentType: media.ContentType,
+		Size:        media.Size,
+		Width:       media.Width,
+		Height:      media.Height,
+		Hash:        media.Hash,
+		CreatedAt:   media.CreatedAt.Format("2006-01-02 15:04:05"),
+
+		VariantStatus: media.VariantStatus,
+		Srcset:        mediaSrcset(media.URL, media.Width, variants, ""),
+	}
+	for _, v := range variants {
+		resp.Variants = append(resp.Variants, &types.MediaVariantResponse{
+			Name:        v.Name,
+			URL:         v.URL,
+			ContentType: v.ContentType,
+			Size:        v.Size,
+			Width:       v.Width,
+			Height:      v.Height,
+		})
+	}
+	return resp
+}
+
+// syncMediaReferences 根据文章当前的封面和正文图片重建文件引用，在保存文章的事务中调用
+func syncMediaReferences(tx *gorm.DB, site config.SiteConfig, articleID uint) error {
+	var article model.Article
+	if err := tx.Select("id", "author_id", "cover", "content").First(&article, articleID).Error; err != nil {
+		return err
+	}
+
+	fields := make(map[string][]string) // 地址 -> 引用位置
+	if article.Cover != "" {
+		u := normalizeMediaURL(site, article.Cover)
+		fields[u] = append(fields[u], model.MediaRefCover)
+	}
+	for _, b := range richtext.Parse(article.Content) {
+		if b.Type != richtext.BlockImage || b.Text == "" {
+			continue
+		}
+		u := normalizeMediaURL(site, b.Text)
+		if len(fields[u]) == 0 || fields[u][len(fields[u])-1] != model.MediaRefContent {
+			fields[u] = append(fields[u], model.MediaRefContent)
+		}
+	}
+
+	if err := tx.Where("article_id = ?", articleID).Delete(&model.MediaReference{}).Error; err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	urls := make([]string, 0, len(fields))
+	for u := range fields {
+		urls = append(urls, u)
+	}
+	var media []model.Media
+	if err := tx.Select("id", "owner_id", "url").Where("url IN ?", urls).Order("id").Find(&media).Error; err != nil {
+		return err
+	}
+
+	// 相同内容被多个用户上传时共用地址，优先记在作者自己上传的记录上
+	byURL := make(map[string]model.Media, len(media))
+	for _, m := range media {
+		if picked, ok := byURL[m.URL]; !ok || m.OwnerID == article.AuthorID && picked.OwnerID != article.AuthorID {
+			byURL[m.URL] = m
+		}
+	}
+
+	var refs []model.MediaReference
+	for u, m := range byURL {
+		for _, field := range fields[u] {
+			refs = append(refs, model.MediaReference{MediaID: m.ID, ArticleID: articleID, Field: field})
+		}
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&refs).Error
+}
+
+// normalizeMediaURL 站内文件可能以完整地址引用，统一去掉站点地址再匹配
+func normalizeMediaURL(site config.SiteConfig, u string) string {
+	u = strings.TrimSpace(u)
+	if prefix := siteURL(site); prefix != "" && strings.HasPrefix(u, prefix+"/") {
+		return strings.TrimPrefix(u, prefix)
+	}
+	return u
+}
+
+// mediaRefCounts 每个文件被多少篇文章引用。回收站中的文章仍可恢复，其引用也计算在内，
+// 永久删除时才会解除
+func mediaRefCounts(db *gorm.DB, ids []uint) map[uint]int64 {
+	counts := make(map[uint]int64, len(ids))
+	if len(ids) == 0 {
+		return counts
+	}
+
+	var rows []struct {
+		MediaID uint
+		Total   int64
+	}
+	db.Model(&model.MediaReference{}).
+		Select("media_references.media_id, COUNT(DISTINCT media_references.article_id) AS total").
+		Joins("JOIN articles ON articles.id = media_references.article_id").
+		Where("media_references.media_id IN ?", ids).
+		Group("media_references.media_id").
+		Scan(&rows)
+	for _, row := range rows {
+		counts[row.MediaID] = row.Total
+	}
+	return counts
+}
+
+// mediaStorageKey 按内容哈希生成存储 key，前两位作为子目录避免单个目录文件过多
+func mediaStorageKey(hash, contentType string) string {
+	ext, ok := mediaExtensions[contentType]
+	if !ok {
+		if exts, _ := mime.ExtensionsByType(contentType); len(exts) > 0 {
+			ext = exts[0]
+		}
+	}
+	return "media/" + hash[:2] + "/" + hash + ext
+}
+
+// sniffContentType 根据文件开头的内容识别类型，去掉 charset 等参数
+func sniffContentType(file io.ReadSeeker) (string, error) {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+
+	contentType := http.DetectContentType(head[:n])
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = strings.TrimSpace(contentType[:i])
+	}
+	return contentType, nil
+}
+
+func mediaTypeAllowed(cfg config.MediaConfig, contentType string) bool {
+	allowed := cfg.AllowedTypes
+	if len(allowed) == 0 {
+		allowed = defaultMediaTypes
+	}
+	for _, t := range allowed {
+		if t == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20 && n%(1<<20) == 0:
+		return strconv.FormatInt(n>>20, 10) + "MB"
+	case n >= 1<<10 && n%(1<<10) == 0:
+		return strconv.FormatInt(n>>10, 10) + "KB"
+	default:
+		return strconv.FormatInt(n, 10) + " 字节"
+	}
+}
@@ -0,0 +1,147 @@
+package logic
+
+import (
+	"context"
+
+	"acupofcoffee/api/internal/svc"
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/errorx"
+	"acupofcoffee/common/utils"
+	"acupofcoffee/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 通知中评论摘要的最大字符数
+const notificationExcerptLength = 100
+
+type NotificationLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewNotificationLogic(ctx context.Context, svcCtx *svc.ServiceContext) *NotificationLogic {
+	return &NotificationLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// List 当前用户的通知，按时间倒序
+func (l *NotificationLogic) List(req *types.NotificationListRequest) (*types.NotificationListResponse, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+
+	var unread int64
+	l.svcCtx.DB.Model(&model.Notification{}).Where("user_id = ? AND is_read = ?", userID, false).Count(&unread)
+
+	query := l.svcCtx.DB.Model(&model.Notification{}).Where("user_id = ?", userID)
+	if req.UnreadOnly {
+		query = query.Where("is_read = ?", false)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var notes []model.Notification
+	if err := query.Preload("Actor").
+		Order("created_at DESC, id DESC").
+		Scopes(model.Paginate(req.Page, req.PageSize)).
+		Find(&notes).Error; err != nil {
+		l.Logger.Errorf("list notifications error: %v", err)
+		return nil, errorx.NewDefaultError("获取通知失败")
+	}
+
+	articleIDs := make([]uint, 0, len(notes))
+	commentIDs := make([]uint, 0, len(notes))
+	for _, n := range notes {
+		articleIDs = append(articleIDs, n.ArticleID)
+		commentIDs = append(commentIDs, n.CommentID)
+	}
+	titles := make(map[uint]string, len(articleIDs))
+	excerpts := make(map[uint]string, len(commentIDs))
+	if len(notes) > 0 {
+		var articles []model.Article
+		l.svcCtx.DB.Select("id", "title").Where("id IN ?", articleIDs).Find(&articles)
+		for _, a := range articles {
+			titles[a.ID] = a.Title
+		}
+
+		var comments []model.Comment
+		l.svcCtx.DB.Select("id", "body").Where("id IN ?", commentIDs).Find(&comments)
+		for _, c := range comments {
+			excerpts[c.ID] = utils.TruncateRunes(c.Body, notificationExcerptLength)
+		}
+	}
+
+	list := make([]*types.NotificationResponse, len(notes))
+	for i, n := range notes {
+		item := &types.NotificationResponse{
+			ID:           n.ID,
+			Type:         n.Type,
+			ActorID:      n.ActorID,
+			ArticleID:    n.ArticleID,
+			ArticleTitle: titles[n.ArticleID],
+			CommentID:    n.CommentID,
+			Excerpt:      excerpts[n.CommentID],
+			Read:         n.IsRead,
+			CreatedAt:    n.CreatedAt.Format("2006-01-02 15:04:05"),
+		}
+		if n.Actor != nil {
+			item.ActorName = userDisplayName(*n.Actor)
+		}
+		list[i] = item
+	}
+
+	return &types.NotificationListResponse{
+		PageResponse: types.PageResponse{
+			Total:    total,
+			Page:     req.Page,
+			PageSize: req.PageSize,
+			List:     list,
+		},
+		Unread: unread,
+	}, nil
+}
+
+// MarkRead 标记一条通知为已读
+func (l *NotificationLogic) MarkRead(id uint) error {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return errorx.NewUnauthorizedError("未登录")
+	}
+
+	result := l.svcCtx.DB.Model(&model.Notification{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("is_read", true)
+	if result.Error != nil {
+		l.Logger.Errorf("mark notification read error: %v", result.Error)
+		return errorx.NewDefaultError("操作失败")
+	}
+	if result.RowsAffected == 0 {
+		return errorx.NewNotFoundError("通知不存在")
+	}
+
+	return nil
+}
+
+// MarkAllRead 将当前用户的通知全部标记为已读
+func (l *NotificationLogic) MarkAllRead() error {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return errorx.NewUnauthorizedError("未登录")
+	}
+
+	if err := l.svcCtx.DB.Model(&model.Notification{}).
+		Where("user_id = ? AND is_read = ?", userID, false).
+		Update("is_read", true).Error; err != nil {
+		l.Logger.Errorf("mark all notifications read error: %v", err)
+		return errorx.NewDefaultError("操作失败")
+	}
+
+	return nil
+}
@@ -0,0 +1,357 @@
+package logic
+
+import (
+	"context"
+	"errors"
+
+	"acupofcoffee/api/internal/svc"
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/errorx"
+	"acupofcoffee/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReactionLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewReactionLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ReactionLogic {
+	return &ReactionLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// Like 点赞，重复点赞不会重复计数
+func (l *ReactionLogic) Like(articleID uint) (*types.LikeResponse, error) {
+	return l.setLike(articleID, true)
+}
+
+// Unlike 取消点赞，未点赞时直接返回
+func (l *ReactionLogic) Unlike(articleID uint) (*types.LikeResponse, error) {
+	return l.setLike(articleID, false)
+}
+
+func (l *ReactionLogic) setLike(articleID uint, liked bool) (*types.LikeResponse, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+	article, err := l.findArticle(articleID, liked)
+	if err != nil {
+		return nil, err
+	}
+
+	// 只有记录真正变化时才调整计数，计数和记录在同一事务中更新
+	changed := false
+	var like model.ArticleLike
+	err = l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		var result *gorm.DB
+		delta := 1
+		if liked {
+			like = model.ArticleLike{ArticleID: article.ID, UserID: userID}
+			result = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&like)
+		} else {
+			// 取消时需要点赞时间，按当时的权重扣回热度
+			if err := tx.Where("article_id = ? AND user_id = ?", article.ID, userID).First(&like).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return nil
+				}
+				return err
+			}
+			result = tx.Delete(&like)
+			delta = -1
+		}
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		changed = true
+		return tx.Model(&model.Article{}).Where("id = ?", article.ID).
+			UpdateColumn("like_count", gorm.Expr("like_count + ?", delta)).Error
+	})
+	if err != nil {
+		l.Logger.Errorf("set like error: %v", err)
+		return nil, errorx.NewDefaultError("操作失败")
+	}
+	if changed {
+		if liked {
+			l.svcCtx.Ranking.Like(article.ID)
+		} else {
+			l.svcCtx.Ranking.Unlike(article.ID, like.CreatedAt)
+		}
+	}
+
+	var likeCount int64
+	l.svcCtx.DB.Model(&model.Article{}).Where("id = ?", article.ID).Pluck("like_count", &likeCount)
+
+	return &types.LikeResponse{
+		ArticleID: article.ID,
+		Liked:     liked,
+		LikeCount: likeCount,
+	}, nil
+}
+
+// React 添加表情回应
+func (l *ReactionLogic) React(req *types.ArticleReactionRequest) ([]*types.ReactionSummary, error) {
+	return l.setReaction(req, true)
+}
+
+// Unreact 取消表情回应
+func (l *ReactionLogic) Unreact(req *types.ArticleReactionRequest) ([]*types.ReactionSummary, error) {
+	return l.setReaction(req, false)
+}
+
+func (l *ReactionLogic) setReaction(req *types.ArticleReactionRequest, reacted bool) ([]*types.ReactionSummary, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+	if !isReactionEmoji(req.Emoji) {
+		return nil, errorx.NewParamError("不支持的表情")
+	}
+	article, err := l.findArticle(req.ID, reacted)
+	if err != nil {
+		return nil, err
+	}
+
+	if reacted {
+		err = l.svcCtx.DB.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&model.ArticleReaction{ArticleID: article.ID, UserID: userID, Emoji: req.Emoji}).Error
+	} else {
+		err = l.svcCtx.DB.Where("article_id = ? AND user_id = ? AND emoji = ?", article.ID, userID, req.Emoji).
+			Delete(&model.ArticleReaction{}).Error
+	}
+	if err != nil {
+		l.Logger.Errorf("set reaction error: %v", err)
+		return nil, errorx.NewDefaultError("操作失败")
+	}
+
+	return articleReactions(l.svcCtx.DB, article.ID, userID)
+}
+
+// Bookmark 收藏文章
+func (l *ReactionLogic) Bookmark(articleID uint) (*types.BookmarkResponse, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+	article, err := l.findArticle(articleID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := l.svcCtx.DB.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.Bookmark{UserID: userID, ArticleID: article.ID}).Error; err != nil {
+		l.Logger.Errorf("bookmark error: %v", err)
+		return nil, errorx.NewDefaultError("收藏失败")
+	}
+
+	return &types.BookmarkResponse{ArticleID: article.ID, Bookmarked: true}, nil
+}
+
+// Unbookmark 取消收藏，文章已删除或下线时也可以取消
+func (l *ReactionLogic) Unbookmark(articleID uint) (*types.BookmarkResponse, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+
+	if err := l.svcCtx.DB.Where("user_id = ? AND article_id = ?", userID, articleID).
+		Delete(&model.Bookmark{}).Error; err != nil {
+		l.Logger.Errorf("unbookmark error: %v", err)
+		return nil, errorx.NewDefaultError("取消收藏失败")
+	}
+
+	return &types.BookmarkResponse{ArticleID: articleID, Bookmarked: false}, nil
+}
+
+// ListBookmarks 当前用户的收藏，按收藏时间倒序，只列出仍然公开的文章
+func (l *ReactionLogic) ListBookmarks(req *types.PageRequest) (*types.PageResponse, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+
+	query := l.svcCtx.DB.Table("bookmarks").
+		Joins("JOIN articles ON articles.id = bookmarks.article_id AND articles.deleted_at IS NULL").
+		Where("bookmarks.user_id = ? AND articles.status = ?", userID, model.ArticleStatusPublished)
+
+	var total int64
+	query.Count(&total)
+
+	var rows []struct {
+		model.Bookmark
+		Title    string
+		Slug     string
+		Cover    string
+		Summary  string
+		AuthorID uint
+	}
+	if err := query.Select("bookmarks.*, articles.title, articles.slug, articles.cover, articles.summary, articles.author_id").
+		Order("bookmarks.created_at DESC, bookmarks.id DESC").
+		Scopes(model.Paginate(req.Page, req.PageSize)).
+		Scan(&rows).Error; err != nil {
+		l.Logger.Errorf("list bookmarks error: %v", err)
+		return nil, errorx.NewDefaultError("获取收藏失败")
+	}
+
+	authorIDs := make([]uint, len(rows))
+	for i, row := range rows {
+		authorIDs[i] = row.AuthorID
+	}
+	authors := make(map[uint]string, len(rows))
+	if len(authorIDs) > 0 {
+		var users []model.User
+		l.svcCtx.DB.Select("id", "username", "nickname").Where("id IN ?", authorIDs).Find(&users)
+		for _, u := range users {
+			authors[u.ID] = u.Nickname
+			if u.Nickname == "" {
+				authors[u.ID] = u.Username
+			}
+		}
+	}
+
+	list := make([]*types.BookmarkItem, len(rows))
+	for i, row := range rows {
+		list[i] = &types.BookmarkItem{
+			ArticleID:    row.ArticleID,
+			Title:        row.Title,
+			Slug:         row.Slug,
+			Cover:        row.Cover,
+			Summary:      row.Summary,
+			AuthorID:     row.AuthorID,
+			AuthorName:   authors[row.AuthorID],
+			BookmarkedAt: row.CreatedAt.Format("2006-01-02 15:04:05"),
+		}
+	}
+
+	return &types.PageResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		List:     list,
+	}, nil
+}
+
+// ReconcileCounters 按点赞记录校正文章的点赞数，由后台任务周期调用
+func (l *ReactionLogic) ReconcileCounters() error {
+	result := l.svcCtx.DB.Exec(`UPDATE articles SET like_count = (
+		SELECT COUNT(*) FROM article_likes WHERE article_likes.article_id = articles.id
+	) WHERE like_count <> (
+		SELECT COUNT(*) FROM article_likes WHERE article_likes.article_id = articles.id
+	)`)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected > 0 {
+		l.Logger.Infof("reconcile counters: %d articles corrected", result.RowsAffected)
+	}
+	return nil
+}
+
+// findArticle 查找要点赞、回应或收藏的文章。只有已发布的文章可以新增，
+// 文章下线或退回审核后仍然可以取消，计数随之更新
+func (l *ReactionLogic) findArticle(id uint, adding bool) (*model.Article, error) {
+	query := l.svcCtx.DB.Select("id", "status")
+	if adding {
+		query = query.Where("status = ?", model.ArticleStatusPublished)
+	}
+
+	var article model.Article
+	if err := query.First(&article, id).Error; err != nil {
+		return nil, errorx.NewNotFoundError("文章不存在")
+	}
+	return &article, nil
+}
+
+// articleReactions 文章各表情的回应数，userID 不为 0 时标记当前用户的回应
+func articleReactions(db *gorm.DB, articleID, userID uint) ([]*types.ReactionSummary, error) {
+	var rows []struct {
+		Emoji string
+		Total int64
+	}
+	if err := db.Model(&model.ArticleReaction{}).
+		Select("emoji, COUNT(*) AS total").
+		Where("article_id = ?", articleID).
+		Group("emoji").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	mine := make(map[string]bool)
+	if userID > 0 {
+		var emojis []string
+		db.Model(&model.ArticleReaction{}).
+			Where("article_id = ? AND user_id = ?", articleID, userID).
+			Pluck("emoji", &emojis)
+		for _, e := range emojis {
+			mine[e] = true
+		}
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Emoji] = row.Total
+	}
+
+	// 按固定顺序输出，没有回应的表情不返回
+	list := make([]*types.ReactionSummary, 0, len(rows))
+	for _, emoji := range model.ReactionEmojis {
+		if counts[emoji] == 0 {
+			continue
+		}
+		list = append(list, &types.ReactionSummary{
+			Emoji:       emoji,
+			Count:       counts[emoji],
+			ReactedByMe: mine[emoji],
+		})
+	}
+	return list, nil
+}
+
+// fillViewerState 标记当前用户是否点赞、收藏了列表中的文章
+func fillViewerState(db *gorm.DB, userID uint, list ...*types.ArticleResponse) {
+	if userID == 0 || len(list) == 0 {
+		return
+	}
+
+	ids := make([]uint, len(list))
+	for i, item := range list {
+		ids[i] = item.ID
+	}
+
+	var liked, bookmarked []uint
+	db.Model(&model.ArticleLike{}).Where("user_id = ? AND article_id IN ?", userID, ids).Pluck("article_id", &liked)
+	db.Model(&model.Bookmark{}).Where("user_id = ? AND article_id IN ?", userID, ids).Pluck("article_id", &bookmarked)
+
+	likedSet := make(map[uint]bool, len(liked))
+	for _, id := range liked {
+		likedSet[id] = true
+	}
+	bookmarkedSet := make(map[uint]bool, len(bookmarked))
+	for _, id := range bookmarked {
+		bookmarkedSet[id] = true
+	}
+
+	for _, item := range list {
+		item.LikedByMe = likedSet[item.ID]
+		item.BookmarkedByMe = bookmarkedSet[item.ID]
+	}
+}
+
+func isReactionEmoji(emoji string) bool {
+	for _, e := range model.ReactionEmojis {
+		if e == emoji {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package logic
+
+import (
+	"errors"
+	"testing"
+
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/errorx"
+	"acupofcoffee/model"
+)
+
+func TestUnlikeAfterArchive(t *testing.T) {
+	svcCtx := newTestServiceContext(t)
+	a, err := NewArticleLogic(userContext(1), svcCtx).Create(&types.CreateArticleRequest{Title: "Title", Content: "content", Status: model.ArticleStatusPublished})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	reader := NewReactionLogic(userContext(2), svcCtx)
+	if _, err := reader.Like(a.ID); err != nil {
+		t.Fatalf("like: %v", err)
+	}
+	if err := svcCtx.DB.Model(&model.Article{}).Where("id = ?", a.ID).Update("status", model.ArticleStatusArchived).Error; err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+
+	// 下线的文章不能再点赞，但可以取消
+	var codeErr *errorx.CodeError
+	if _, err := NewReactionLogic(userContext(3), svcCtx).Like(a.ID); !errors.As(err, &codeErr) || codeErr.Code != errorx.CodeNotFound {
+		t.Fatalf("like archived: got %v, want not found", err)
+	}
+	resp, err := reader.Unlike(a.ID)
+	if err != nil {
+		t.Fatalf("unlike archived: %v", err)
+	}
+	if resp.Liked || resp.LikeCount != 0 {
+		t.Fatalf("unlike archived: liked=%v likeCount=%d", resp.Liked, resp.LikeCount)
+	}
+}
@@ -0,0 +1,207 @@
+package logic
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"acupofcoffee/api/internal/svc"
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/errorx"
+	"acupofcoffee/common/utils"
+	"acupofcoffee/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+const (
+	ScheduleActionPublish   = "publish"
+	ScheduleActionUnpublish = "unpublish"
+)
+
+type ScheduleLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewScheduleLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ScheduleLogic {
+	return &ScheduleLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// SetSchedule 设置文章的定时发布/下线时间，传空字符串表示取消
+func (l *ScheduleLogic) SetSchedule(req *types.ArticleScheduleRequest) (*types.ArticleScheduleResponse, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+
+	var article model.Article
+	if err := l.svcCtx.DB.First(&article, req.ID).Error; err != nil {
+		return nil, errorx.NewNotFoundError("文章不存在")
+	}
+	if !isArticleAuthor(l.svcCtx.DB, &article, userID) {
+		return nil, errorx.NewForbiddenError("无权操作该文章")
+	}
+
+	publishAt, unpublishAt, err := parseSchedule(req.PublishAt, req.UnpublishAt)
+	if err != nil {
+		return nil, err
+	}
+	if publishAt != nil && article.Status == model.ArticleStatusPublished {
+		return nil, errorx.NewParamError("文章已发布")
+	}
+
+	if err := l.svcCtx.DB.Model(&article).Updates(map[string]interface{}{
+		"publish_at":   publishAt,
+		"unpublish_at": unpublishAt,
+	}).Error; err != nil {
+		l.Logger.Errorf("set article schedule error: %v", err)
+		return nil, errorx.NewDefaultError("设置定时发布失败")
+	}
+
+	return &types.ArticleScheduleResponse{
+		ArticleID:   article.ID,
+		PublishAt:   formatOptionalTime(publishAt),
+		UnpublishAt: formatOptionalTime(unpublishAt),
+	}, nil
+}
+
+// ListUpcoming 当前用户即将执行的定时发布/下线计划，按时间排序
+func (l *ScheduleLogic) ListUpcoming() ([]*types.ArticleScheduleItem, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+
+	var articles []model.Article
+	if err := l.svcCtx.DB.Select("id", "title", "status", "publish_at", "unpublish_at").
+		Where("author_id = ? AND (publish_at IS NOT NULL OR unpublish_at IS NOT NULL)", userID).
+		Find(&articles).Error; err != nil {
+		l.Logger.Errorf("list article schedule error: %v", err)
+		return nil, errorx.NewDefaultError("获取发布计划失败")
+	}
+
+	list := make([]*types.ArticleScheduleItem, 0, len(articles))
+	for _, article := range articles {
+		if article.PublishAt != nil {
+			list = append(list, newScheduleItem(&article, ScheduleActionPublish, *article.PublishAt))
+		}
+		if article.UnpublishAt != nil {
+			list = append(list, newScheduleItem(&article, ScheduleActionUnpublish, *article.UnpublishAt))
+		}
+	}
+
+	// 时间格式固定，可以直接按字符串排序
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].At < list[j].At
+	})
+	return list, nil
+}
+
+// RunDue 执行已到期的定时发布和下线，由后台任务周期调用。
+// 开启强制审核时，只有审核通过的文章会被定时发布，其余的等审核通过后再发布。
+func (l *ScheduleLogic) RunDue() error {
+	now := time.Now()
+
+	var due []model.Article
+	if err := l.svcCtx.DB.Where("(publish_at IS NOT NULL AND publish_at <= ?) OR (unpublish_at IS NOT NULL AND unpublish_at <= ?)", now, now).
+		Order("id ASC").
+		Find(&due).Error; err != nil {
+		return err
+	}
+
+	var published, unpublished int
+	for i := range due {
+		article := &due[i]
+		err := l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+			if article.PublishAt != nil && !article.PublishAt.After(now) {
+				if article.Status != model.ArticleStatusPublished {
+					// 定时发布代表作者执行，不满足发布条件（如未审核通过）时保留计划
+					if checkTransition(l.svcCtx.Config.Workflow, article.Status, model.ArticleStatusPublished, actorAuthor) != nil {
+						return nil
+					}
+					if err := transitionArticle(tx, article, model.ArticleStatusPublished, 0, "定时发布"); err != nil {
+						return err
+					}
+					published++
+				}
+				if err := tx.Model(article).Update("publish_at", nil).Error; err != nil {
+					return err
+				}
+			}
+
+			if article.UnpublishAt != nil && !article.UnpublishAt.After(now) && article.PublishAt == nil {
+				if article.Status == model.ArticleStatusPublished {
+					if err := transitionArticle(tx, article, model.ArticleStatusArchived, 0, "定时下线"); err != nil {
+						return err
+					}
+					unpublished++
+				}
+				if err := tx.Model(article).Update("unpublish_at", nil).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			l.Logger.Errorf("run schedule for article %d error: %v", article.ID, err)
+		}
+	}
+
+	if published > 0 || unpublished > 0 {
+		l.Logger.Infof("scheduled publish: %d published, %d unpublished", published, unpublished)
+	}
+	return nil
+}
+
+// parseSchedule 解析并校验定时发布/下线时间
+func parseSchedule(publishAt, unpublishAt string) (*time.Time, *time.Time, error) {
+	now := time.Now()
+
+	var publish, unpublish *time.Time
+	if publishAt != "" {
+		t, err := utils.ParseDateTime(publishAt)
+		if err != nil {
+			return nil, nil, errorx.NewParamError("发布时间格式错误")
+		}
+		if !t.After(now) {
+			return nil, nil, errorx.NewParamError("发布时间必须晚于当前时间")
+		}
+		publish = &t
+	}
+	if unpublishAt != "" {
+		t, err := utils.ParseDateTime(unpublishAt)
+		if err != nil {
+			return nil, nil, errorx.NewParamError("下线时间格式错误")
+		}
+		if !t.After(now) || (publish != nil && !t.After(*publish)) {
+			return nil, nil, errorx.NewParamError("下线时间必须晚于当前时间和发布时间")
+		}
+		unpublish = &t
+	}
+
+	return publish, unpublish, nil
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
+func newScheduleItem(article *model.Article, action string, at time.Time) *types.ArticleScheduleItem {
+	return &types.ArticleScheduleItem{
+		ArticleID: article.ID,
+		Title:     article.Title,
+		Status:    article.Status,
+		Action:    action,
+		At:        at.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -0,0 +1,132 @@
+package logic
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"acupofcoffee/api/internal/config"
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/richtext"
+	"acupofcoffee/common/utils"
+	"acupofcoffee/model"
+)
+
+const (
+	seoDescriptionLength = 160 // 搜索结果摘要的建议长度
+	seoHeadlineLength    = 110 // schema.org 建议的标题长度上限
+)
+
+// siteURL 站点地址，不带末尾斜杠
+func siteURL(site config.SiteConfig) string {
+	return strings.TrimRight(site.URL, "/")
+}
+
+// articleURL 文章的规范链接，没有 slug 时使用文章ID
+func articleURL(site config.SiteConfig, article *model.Article) string {
+	slug := article.Slug
+	if slug == "" {
+		slug = strconv.FormatUint(uint64(article.ID), 10)
+	}
+	return siteURL(site) + "/articles/" + slug
+}
+
+// absoluteURL 站内相对路径补全为完整地址，其他非 http(s) 地址视为无效
+func absoluteURL(site config.SiteConfig, u string) string {
+	if strings.HasPrefix(u, "/") && !strings.HasPrefix(u, "//") {
+		return siteURL(site) + u
+	}
+	if strings.HasPrefix(u, "http://") || strings.HasPrefix(u, "https://") {
+		return u
+	}
+	return ""
+}
+
+// articleDescription 文章摘要，未填写时从正文截取
+func articleDescription(article *model.Article, max int) string {
+	if article.Summary != "" {
+		return article.Summary
+	}
+	return utils.TruncateRunes(richtext.PlainText(richtext.Parse(article.Content)), max)
+}
+
+// articleSEO 由标题、摘要、封面和作者生成文章页的元数据
+func articleSEO(site config.SiteConfig, article *model.Article) *types.ArticleSEO {
+	canonical := articleURL(site, article)
+	description := articleDescription(article, seoDescriptionLength)
+	image := absoluteURL(site, article.Cover)
+
+	author := ""
+	if article.Author != nil {
+		author = userDisplayName(*article.Author)
+	}
+	tags := make([]string, 0, len(article.Tags))
+	for _, tag := range article.Tags {
+		tags = append(tags, tag.Name)
+	}
+
+	seo := &types.ArticleSEO{
+		CanonicalURL: canonical,
+		Title:        article.Title + " - " + site.Name,
+		Description:  description,
+		OpenGraph: &types.OpenGraphMeta{
+			Type:         "article",
+			Title:        article.Title,
+			Description:  description,
+			URL:          canonical,
+			Image:        image,
+			SiteName:     site.Name,
+			Locale:       strings.ReplaceAll(site.Language, "-", "_"),
+			ModifiedTime: article.UpdatedAt.Format(time.RFC3339),
+			Author:       author,
+			Tags:         tags,
+		},
+		Twitter: &types.TwitterCardMeta{
+			Card:        "summary",
+			Title:       article.Title,
+			Description: description,
+			Image:       image,
+		},
+	}
+	if image != "" {
+		seo.Twitter.Card = "summary_large_image"
+	}
+
+	ld := map[string]interface{}{
+		"@context":     "https://schema.org",
+		"@type":        "Article",
+		"headline":     utils.TruncateRunes(article.Title, seoHeadlineLength),
+		"description":  description,
+		"dateModified": article.UpdatedAt.Format(time.RFC3339),
+		"inLanguage":   site.Language,
+		"url":          canonical,
+		"mainEntityOfPage": map[string]interface{}{
+			"@type": "WebPage",
+			"@id":   canonical,
+		},
+		"publisher": map[string]interface{}{
+			"@type": "Organization",
+			"name":  site.Name,
+			"url":   siteURL(site) + "/",
+		},
+	}
+	if article.PublishedAt != nil {
+		seo.OpenGraph.PublishedTime = article.PublishedAt.Format(time.RFC3339)
+		ld["datePublished"] = seo.OpenGraph.PublishedTime
+	}
+	if image != "" {
+		ld["image"] = []string{image}
+	}
+	if author != "" {
+		ld["author"] = map[string]interface{}{
+			"@type": "Person",
+			"name":  author,
+		}
+	}
+	if len(tags) > 0 {
+		ld["keywords"] = strings.Join(tags, ",")
+	}
+	seo.JSONLD = ld
+
+	return seo
+}
@@ -0,0 +1,342 @@
+package logic
+
+import (
+	"context"
+	"strings"
+
+	"acupofcoffee/api/internal/svc"
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/errorx"
+	"acupofcoffee/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type SeriesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewSeriesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SeriesLogic {
+	return &SeriesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// Create 创建系列
+func (l *SeriesLogic) Create(req *types.CreateSeriesRequest) (*types.SeriesResponse, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return nil, errorx.NewParamError("系列标题不能为空")
+	}
+
+	series := model.Series{
+		Title:       title,
+		Description: req.Description,
+		AuthorID:    userID,
+	}
+	if err := l.svcCtx.DB.Create(&series).Error; err != nil {
+		l.Logger.Errorf("create series error: %v", err)
+		return nil, errorx.NewDefaultError("创建系列失败")
+	}
+
+	return l.seriesToResponse(&series, nil), nil
+}
+
+// Update 更新系列标题和描述
+func (l *SeriesLogic) Update(req *types.UpdateSeriesRequest) (*types.SeriesResponse, error) {
+	series, err := l.findOwnSeries(req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if title := strings.TrimSpace(req.Title); title != "" {
+		updates["title"] = title
+	}
+	if req.Description != "" {
+		updates["description"] = req.Description
+	}
+	if len(updates) > 0 {
+		if err := l.svcCtx.DB.Model(series).Updates(updates).Error; err != nil {
+			l.Logger.Errorf("update series error: %v", err)
+			return nil, errorx.NewDefaultError("更新系列失败")
+		}
+	}
+
+	return l.detail(series, false)
+}
+
+// Delete 删除系列（文章本身不受影响）
+func (l *SeriesLogic) Delete(id uint) error {
+	series, err := l.findOwnSeries(id)
+	if err != nil {
+		return err
+	}
+
+	err = l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("series_id = ?", series.ID).Delete(&model.SeriesArticle{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(series).Error
+	})
+	if err != nil {
+		l.Logger.Errorf("delete series error: %v", err)
+		return errorx.NewDefaultError("删除系列失败")
+	}
+
+	return nil
+}
+
+// SetArticles 设置系列中的文章及顺序
+func (l *SeriesLogic) SetArticles(req *types.SetSeriesArticlesRequest) (*types.SeriesResponse, error) {
+	series, err := l.findOwnSeries(req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uint]bool, len(req.ArticleIDs))
+	for _, id := range req.ArticleIDs {
+		if seen[id] {
+			return nil, errorx.NewParamError("文章重复")
+		}
+		seen[id] = true
+	}
+
+	if len(req.ArticleIDs) > 0 {
+		var count int64
+		l.svcCtx.DB.Model(&model.Article{}).
+			Where("id IN ? AND author_id = ?", req.ArticleIDs, series.AuthorID).
+			Count(&count)
+		if int(count) != len(req.ArticleIDs) {
+			return nil, errorx.NewParamError("文章不存在或不属于该作者")
+		}
+
+		l.svcCtx.DB.Model(&model.SeriesArticle{}).
+			Where("article_id IN ? AND series_id <> ?", req.ArticleIDs, series.ID).
+			Count(&count)
+		if count > 0 {
+			return nil, errorx.NewParamError("文章已属于其他系列")
+		}
+	}
+
+	err = l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("series_id = ?", series.ID).Delete(&model.SeriesArticle{}).Error; err != nil {
+			return err
+		}
+		if len(req.ArticleIDs) == 0 {
+			return nil
+		}
+
+		items := make([]model.SeriesArticle, len(req.ArticleIDs))
+		for i, id := range req.ArticleIDs {
+			items[i] = model.SeriesArticle{
+				SeriesID:  series.ID,
+				ArticleID: id,
+				Position:  i + 1,
+			}
+		}
+		return tx.Create(&items).Error
+	})
+	if err != nil {
+		l.Logger.Errorf("set series articles error: %v", err)
+		return nil, errorx.NewDefaultError("更新系列文章失败")
+	}
+
+	return l.detail(series, false)
+}
+
+// Get 系列详情，非作者只能看到已发布的文章
+func (l *SeriesLogic) Get(id uint) (*types.SeriesResponse, error) {
+	var series model.Series
+	if err := l.svcCtx.DB.Preload("Author").First(&series, id).Error; err != nil {
+		return nil, errorx.NewNotFoundError("系列不存在")
+	}
+
+	userID, _ := l.ctx.Value("userId").(uint)
+	return l.detail(&series, userID != series.AuthorID)
+}
+
+// List 系列列表（公开，只统计已发布文章）
+func (l *SeriesLogic) List(req *types.SeriesListRequest) ([]*types.SeriesResponse, error) {
+	query := l.svcCtx.DB.Preload("Author").Order("created_at DESC")
+	if req.AuthorID > 0 {
+		query = query.Where("author_id = ?", req.AuthorID)
+	}
+
+	return l.list(query, true)
+}
+
+// ListMine 当前用户的系列（统计全部文章）
+func (l *SeriesLogic) ListMine() ([]*types.SeriesResponse, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+
+	query := l.svcCtx.DB.Preload("Author").Where("author_id = ?", userID).Order("created_at DESC")
+	return l.list(query, false)
+}
+
+func (l *SeriesLogic) list(query *gorm.DB, publishedOnly bool) ([]*types.SeriesResponse, error) {
+	var seriesList []model.Series
+	if err := query.Find(&seriesList).Error; err != nil {
+		l.Logger.Errorf("list series error: %v", err)
+		return nil, errorx.NewDefaultError("获取系列列表失败")
+	}
+
+	ids := make([]uint, len(seriesList))
+	for i, s := range seriesList {
+		ids[i] = s.ID
+	}
+
+	counts := make(map[uint]int64, len(ids))
+	if len(ids) > 0 {
+		var rows []struct {
+			SeriesID uint
+			Total    int64
+		}
+		countQuery := l.svcCtx.DB.Table("series_articles").
+			Select("series_articles.series_id, COUNT(*) AS total").
+			Joins("JOIN articles ON articles.id = series_articles.article_id AND articles.deleted_at IS NULL").
+			Where("series_articles.series_id IN ?", ids)
+		if publishedOnly {
+			countQuery = countQuery.Where("articles.status = ?", model.ArticleStatusPublished)
+		}
+		countQuery.Group("series_articles.series_id").Scan(&rows)
+		for _, row := range rows {
+			counts[row.SeriesID] = row.Total
+		}
+	}
+
+	list := make([]*types.SeriesResponse, len(seriesList))
+	for i := range seriesList {
+		list[i] = l.seriesToResponse(&seriesList[i], nil)
+		list[i].ArticleCount = counts[seriesList[i].ID]
+	}
+
+	return list, nil
+}
+
+func (l *SeriesLogic) detail(series *model.Series, publishedOnly bool) (*types.SeriesResponse, error) {
+	items, err := loadSeriesArticles(l.svcCtx.DB, series.ID, publishedOnly)
+	if err != nil {
+		l.Logger.Errorf("load series articles error: %v", err)
+		return nil, errorx.NewDefaultError("获取系列文章失败")
+	}
+
+	return l.seriesToResponse(series, items), nil
+}
+
+func (l *SeriesLogic) findOwnSeries(id uint) (*model.Series, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+
+	var series model.Series
+	if err := l.svcCtx.DB.Preload("Author").First(&series, id).Error; err != nil {
+		return nil, errorx.NewNotFoundError("系列不存在")
+	}
+	if series.AuthorID != userID {
+		return nil, errorx.NewForbiddenError("无权操作该系列")
+	}
+
+	return &series, nil
+}
+
+func (l *SeriesLogic) seriesToResponse(series *model.Series, items []*types.SeriesArticleItem) *types.SeriesResponse {
+	resp := &types.SeriesResponse{
+		ID:           series.ID,
+		Title:        series.Title,
+		Description:  series.Description,
+		AuthorID:     series.AuthorID,
+		ArticleCount: int64(len(items)),
+		Articles:     items,
+		CreatedAt:    series.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:    series.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+
+	if series.Author != nil {
+		resp.AuthorName = series.Author.Nickname
+		if resp.AuthorName == "" {
+			resp.AuthorName = series.Author.Username
+		}
+	}
+
+	return resp
+}
+
+// loadSeriesArticles 按顺序加载系列中的文章，Position 按可见文章重新编号
+func loadSeriesArticles(db *gorm.DB, seriesID uint, publishedOnly bool) ([]*types.SeriesArticleItem, error) {
+	var items []*types.SeriesArticleItem
+
+	query := db.Table("series_articles").
+		Select("articles.id, articles.title, articles.status, series_articles.position").
+		Joins("JOIN articles ON articles.id = series_articles.article_id AND articles.deleted_at IS NULL").
+		Where("series_articles.series_id = ?", seriesID)
+	if publishedOnly {
+		query = query.Where("articles.status = ?", model.ArticleStatusPublished)
+	}
+
+	if err := query.Order("series_articles.position ASC").Scan(&items).Error; err != nil {
+		return nil, err
+	}
+
+	for i, item := range items {
+		item.Position = i + 1
+	}
+	return items, nil
+}
+
+// articleSeriesNav 获取文章所在系列的导航信息，文章不在系列中时返回 nil
+func articleSeriesNav(db *gorm.DB, article *model.Article, publishedOnly bool) *types.ArticleSeriesNav {
+	if publishedOnly && article.Status != model.ArticleStatusPublished {
+		return nil
+	}
+
+	var link model.SeriesArticle
+	if err := db.Where("article_id = ?", article.ID).First(&link).Error; err != nil {
+		return nil
+	}
+
+	var series model.Series
+	if err := db.First(&series, link.SeriesID).Error; err != nil {
+		return nil
+	}
+
+	items, err := loadSeriesArticles(db, series.ID, publishedOnly)
+	if err != nil {
+		return nil
+	}
+
+	nav := &types.ArticleSeriesNav{
+		ID:    series.ID,
+		Title: series.Title,
+		Total: len(items),
+	}
+	for i, item := range items {
+		if item.ID != article.ID {
+			continue
+		}
+		nav.Position = item.Position
+		if i > 0 {
+			nav.Prev = items[i-1]
+		}
+		if i < len(items)-1 {
+			nav.Next = items[i+1]
+		}
+		break
+	}
+
+	return nav
+}
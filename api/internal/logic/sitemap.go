@@ -0,0 +1,121 @@
+package logic
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"acupofcoffee/api/internal/svc"
+	"acupofcoffee/common/errorx"
+	"acupofcoffee/common/sitemap"
+	"acupofcoffee/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type SitemapLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewSitemapLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SitemapLogic {
+	return &SitemapLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// Index 站点地图索引：按文章ID区间分页，只列出包含已发布文章的页，lastmod 取页内文章的最后修改时间。
+// 返回的时间为所有页中最新的修改时间
+func (l *SitemapLogic) Index() ([]sitemap.Entry, time.Time, error) {
+	size := l.pageSize()
+
+	// 逐行读取ID和修改时间，内存占用只与页数有关
+	rows, err := l.svcCtx.DB.Model(&model.Article{}).
+		Select("id", "updated_at").
+		Where("status = ?", model.ArticleStatusPublished).
+		Order("id").
+		Rows()
+	if err != nil {
+		l.Logger.Errorf("query sitemap index error: %v", err)
+		return nil, time.Time{}, errorx.NewDefaultError("获取站点地图失败")
+	}
+	defer rows.Close()
+
+	var entries []sitemap.Entry
+	var latest time.Time
+	page := 0
+	for rows.Next() {
+		var article model.Article
+		if err := l.svcCtx.DB.ScanRows(rows, &article); err != nil {
+			l.Logger.Errorf("scan sitemap index error: %v", err)
+			return nil, time.Time{}, errorx.NewDefaultError("获取站点地图失败")
+		}
+
+		if p := int(article.ID-1)/size + 1; p != page {
+			page = p
+			entries = append(entries, sitemap.Entry{Loc: l.pageURL(page)})
+		}
+		last := &entries[len(entries)-1]
+		if article.UpdatedAt.After(last.LastMod) {
+			last.LastMod = article.UpdatedAt
+		}
+		if article.UpdatedAt.After(latest) {
+			latest = article.UpdatedAt
+		}
+	}
+	if err := rows.Err(); err != nil {
+		l.Logger.Errorf("query sitemap index error: %v", err)
+		return nil, time.Time{}, errorx.NewDefaultError("获取站点地图失败")
+	}
+
+	return entries, latest, nil
+}
+
+// Page 第 page 页的站点地图
+func (l *SitemapLogic) Page(page int) ([]sitemap.Entry, time.Time, error) {
+	if page <= 0 {
+		return nil, time.Time{}, errorx.NewParamError("页码必须大于 0")
+	}
+	size := l.pageSize()
+
+	var articles []model.Article
+	if err := l.svcCtx.DB.Select("id", "slug", "updated_at").
+		Where("status = ? AND id > ? AND id <= ?", model.ArticleStatusPublished, (page-1)*size, page*size).
+		Order("id").
+		Find(&articles).Error; err != nil {
+		l.Logger.Errorf("query sitemap page error: %v", err)
+		return nil, time.Time{}, errorx.NewDefaultError("获取站点地图失败")
+	}
+	if len(articles) == 0 {
+		return nil, time.Time{}, errorx.NewNotFoundError("站点地图不存在")
+	}
+
+	entries := make([]sitemap.Entry, len(articles))
+	var latest time.Time
+	for i := range articles {
+		entries[i] = sitemap.Entry{
+			Loc:     articleURL(l.svcCtx.Config.Site, &articles[i]),
+			LastMod: articles[i].UpdatedAt,
+		}
+		if articles[i].UpdatedAt.After(latest) {
+			latest = articles[i].UpdatedAt
+		}
+	}
+
+	return entries, latest, nil
+}
+
+func (l *SitemapLogic) pageSize() int {
+	size := l.svcCtx.Config.Sitemap.PageSize
+	if size <= 0 || size > sitemap.MaxURLs {
+		size = sitemap.MaxURLs
+	}
+	return size
+}
+
+func (l *SitemapLogic) pageURL(page int) string {
+	return siteURL(l.svcCtx.Config.Site) + "/sitemap.xml?page=" + strconv.Itoa(page)
+}
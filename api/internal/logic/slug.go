@@ -0,0 +1,82 @@
+package logic
+
+import (
+	"strconv"
+
+	"acupofcoffee/common/errorx"
+	"acupofcoffee/common/utils"
+	"acupofcoffee/model"
+
+	"gorm.io/gorm"
+)
+
+const (
+	maxSlugLength     = 80
+	defaultSlugPrefix = "article"
+)
+
+// generateArticleSlug 根据标题生成未被占用的 slug，冲突时追加 -2、-3 等后缀。
+// 该文章自己用过的 slug 可以直接复用。
+func generateArticleSlug(tx *gorm.DB, title string, articleID uint) (string, error) {
+	base := utils.Slugify(title, maxSlugLength)
+	if base == "" {
+		base = defaultSlugPrefix
+	}
+
+	var taken []model.ArticleSlug
+	if err := tx.Where("slug = ? OR slug LIKE ?", base, base+"-%").Find(&taken).Error; err != nil {
+		return "", err
+	}
+
+	owners := make(map[string]uint, len(taken))
+	for _, s := range taken {
+		owners[s.Slug] = s.ArticleID
+	}
+
+	for n := 1; ; n++ {
+		candidate := base
+		if n > 1 {
+			candidate = base + "-" + strconv.Itoa(n)
+		}
+		if owner, ok := owners[candidate]; !ok || (articleID != 0 && owner == articleID) {
+			return candidate, nil
+		}
+	}
+}
+
+// validateCustomSlug 校验作者手动设置的 slug
+func validateCustomSlug(tx *gorm.DB, slug string, articleID uint) error {
+	if len(slug) > maxSlugLength || !utils.IsSlug(slug) {
+		return errorx.NewParamError("链接只能包含小写字母、数字和连字符")
+	}
+
+	var existing model.ArticleSlug
+	err := tx.Where("slug = ?", slug).First(&existing).Error
+	if err == nil && existing.ArticleID != articleID {
+		return errorx.NewParamError("链接已被占用")
+	}
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+
+	return nil
+}
+
+// assignArticleSlug 设置文章的当前 slug，旧 slug 保留在历史表中用于跳转
+func assignArticleSlug(tx *gorm.DB, article *model.Article, slug string) error {
+	if article.Slug == slug {
+		return nil
+	}
+
+	record := model.ArticleSlug{ArticleID: article.ID, Slug: slug}
+	if err := tx.Where(model.ArticleSlug{ArticleID: article.ID, Slug: slug}).
+		FirstOrCreate(&record).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Model(article).UpdateColumn("slug", slug).Error; err != nil {
+		return err
+	}
+	article.Slug = slug
+	return nil
+}
@@ -0,0 +1,168 @@
+package logic
+
+import (
+	"context"
+	"mime"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"acupofcoffee/api/internal/svc"
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/errorx"
+	"acupofcoffee/common/richtext"
+	"acupofcoffee/common/syndication"
+	"acupofcoffee/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const syndicationSummaryLength = 200 // 没有摘要时从正文截取的字符数
+
+type SyndicationLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewSyndicationLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SyndicationLogic {
+	return &SyndicationLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// Feed 最近发布的文章，可按作者、标签、分类筛选。requestURI 为订阅源自身的路径和参数
+func (l *SyndicationLogic) Feed(req *types.SyndicationRequest, requestURI string) (*syndication.Feed, error) {
+	site := l.svcCtx.Config.Site
+
+	feed := &syndication.Feed{
+		Title:       site.Name,
+		Description: site.Description,
+		Link:        siteURL(site) + "/",
+		FeedURL:     siteURL(site) + requestURI,
+		Language:    site.Language,
+		Items:       make([]*syndication.Item, 0),
+	}
+
+	query := l.svcCtx.DB.Model(&model.Article{}).
+		Where("status = ? AND published_at IS NOT NULL", model.ArticleStatusPublished)
+
+	var scopes []string
+	if req.Author != "" {
+		var author model.User
+		if err := l.svcCtx.DB.Select("id", "username", "nickname").
+			Where("username = ? AND status = ?", req.Author, 1).
+			First(&author).Error; err != nil {
+			return nil, errorx.NewNotFoundError("用户不存在")
+		}
+		query = query.Where("author_id = ?", author.ID)
+		scopes = append(scopes, userDisplayName(author))
+	}
+	if req.Tag != "" {
+		var tag model.Tag
+		if err := l.svcCtx.DB.Where("name = ?", req.Tag).First(&tag).Error; err != nil {
+			return nil, errorx.NewNotFoundError("标签不存在")
+		}
+		query = query.Where("id IN (?)", l.svcCtx.DB.Model(&model.ArticleTag{}).
+			Select("article_id").Where("tag_id = ?", tag.ID))
+		scopes = append(scopes, "#"+tag.Name)
+	}
+	if req.Category != "" {
+		var category model.Category
+		if err := l.svcCtx.DB.Where("name = ?", req.Category).First(&category).Error; err != nil {
+			return nil, errorx.NewNotFoundError("分类不存在")
+		}
+		query = query.Where("id IN (?)", l.svcCtx.DB.Model(&model.ArticleCategory{}).
+			Select("article_id").Where("category_id = ?", category.ID))
+		scopes = append(scopes, category.Name)
+	}
+	if len(scopes) > 0 {
+		feed.Title += " - " + strings.Join(scopes, " / ")
+	}
+
+	var articles []model.Article
+	if err := query.Preload("Author").Preload("Tags").Preload("Categories").
+		Order("published_at DESC, id DESC").
+		Limit(l.svcCtx.Config.Syndication.Limit).
+		Find(&articles).Error; err != nil {
+		l.Logger.Errorf("query syndication articles error: %v", err)
+		return nil, errorx.NewDefaultError("获取订阅源失败")
+	}
+
+	for i := range articles {
+		item := l.articleToItem(&articles[i])
+		if item.Updated.After(feed.Updated) {
+			feed.Updated = item.Updated
+		}
+		// 从回收站恢复不更新文章的修改时间，但条目重新出现也是订阅源的变化
+		if restoredAt := articles[i].RestoredAt; restoredAt != nil && restoredAt.After(feed.Updated) {
+			feed.Updated = *restoredAt
+		}
+		feed.Items = append(feed.Items, item)
+	}
+
+	// 文章下线或删除后不在列表中，只看现有条目的修改时间会让 If-Modified-Since 一直命中旧的 304，
+	// 最近一次从已发布流转出去和移入回收站的时间也计入
+	var transition model.ArticleTransition
+	if l.svcCtx.DB.Select("id", "created_at").
+		Where("from_status = ?", model.ArticleStatusPublished).
+		Order("created_at DESC").
+		First(&transition).Error == nil && transition.CreatedAt.After(feed.Updated) {
+		feed.Updated = transition.CreatedAt
+	}
+	var trashed model.Article
+	if l.svcCtx.DB.Unscoped().Select("id", "deleted_at").
+		Where("deleted_at IS NOT NULL AND published_at IS NOT NULL").
+		Order("deleted_at DESC").
+		First(&trashed).Error == nil && trashed.DeletedAt.Time.After(feed.Updated) {
+		feed.Updated = trashed.DeletedAt.Time
+	}
+
+	return feed, nil
+}
+
+func (l *SyndicationLogic) articleToItem(article *model.Article) *syndication.Item {
+	site := l.svcCtx.Config.Site
+
+	item := &syndication.Item{
+		// ID 基于文章ID，修改标题或永久链接后阅读器不会当成新文章
+		ID:        siteURL(site) + "/articles/" + strconv.FormatUint(uint64(article.ID), 10),
+		Title:     article.Title,
+		Link:      articleURL(site, article),
+		Summary:   articleDescription(article, syndicationSummaryLength),
+		Published: *article.PublishedAt,
+		Updated:   article.UpdatedAt,
+	}
+	if l.svcCtx.Config.Syndication.FullContent {
+		item.ContentHTML = richtext.HTML(richtext.Parse(article.Content))
+	}
+	if article.Author != nil {
+		item.Author = userDisplayName(*article.Author)
+	}
+	for _, tag := range article.Tags {
+		item.Categories = append(item.Categories, tag.Name)
+	}
+	for _, category := range article.Categories {
+		item.Categories = append(item.Categories, category.Name)
+	}
+	if cover := absoluteURL(site, article.Cover); cover != "" {
+		item.Image = &syndication.Enclosure{URL: cover, Type: imageType(cover)}
+	}
+
+	return item
+}
+
+// imageType 按扩展名推断图片类型，无法识别时按 JPEG 处理
+func imageType(u string) string {
+	ext := ""
+	if parsed, err := url.Parse(u); err == nil {
+		ext = strings.ToLower(path.Ext(parsed.Path))
+	}
+	if t := mime.TypeByExtension(ext); strings.HasPrefix(t, "image/") {
+		return t
+	}
+	return "image/jpeg"
+}
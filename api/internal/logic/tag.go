@@ -0,0 +1,207 @@
+package logic
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"acupofcoffee/api/internal/svc"
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/errorx"
+	"acupofcoffee/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+const (
+	maxTagNameLength = 50
+	maxArticleTags   = 10
+)
+
+type TagLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewTagLogic(ctx context.Context, svcCtx *svc.ServiceContext) *TagLogic {
+	return &TagLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// List 标签列表（附带已发布文章数）
+func (l *TagLogic) List(req *types.TagListRequest) ([]*types.TagResponse, error) {
+	var rows []struct {
+		ID           uint
+		Name         string
+		ArticleCount int64
+	}
+
+	query := l.svcCtx.DB.Table("tags").
+		Select("tags.id, tags.name, COUNT(articles.id) AS article_count").
+		Joins("LEFT JOIN article_tags ON article_tags.tag_id = tags.id").
+		Joins("LEFT JOIN articles ON articles.id = article_tags.article_id AND articles.deleted_at IS NULL AND articles.status = ?", model.ArticleStatusPublished).
+		Where("tags.deleted_at IS NULL")
+
+	if req.Keyword != "" {
+		query = query.Where("tags.name LIKE ?", "%"+req.Keyword+"%")
+	}
+
+	if err := query.Group("tags.id, tags.name").
+		Order("article_count DESC, tags.id ASC").
+		Scan(&rows).Error; err != nil {
+		l.Logger.Errorf("list tags error: %v", err)
+		return nil, errorx.NewDefaultError("获取标签列表失败")
+	}
+
+	list := make([]*types.TagResponse, len(rows))
+	for i, row := range rows {
+		list[i] = &types.TagResponse{
+			ID:           row.ID,
+			Name:         row.Name,
+			ArticleCount: row.ArticleCount,
+		}
+	}
+
+	return list, nil
+}
+
+// Rename 重命名标签，新名称已存在时需使用合并
+func (l *TagLogic) Rename(req *types.RenameTagRequest) (*types.TagResponse, error) {
+	name, err := normalizeTagName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var tag model.Tag
+	if err := l.svcCtx.DB.First(&tag, req.ID).Error; err != nil {
+		return nil, errorx.NewNotFoundError("标签不存在")
+	}
+
+	var count int64
+	l.svcCtx.DB.Model(&model.Tag{}).Where("name = ? AND id <> ?", name, tag.ID).Count(&count)
+	if count > 0 {
+		return nil, errorx.NewParamError("标签名已存在，请使用合并")
+	}
+
+	if err := l.svcCtx.DB.Model(&tag).Update("name", name).Error; err != nil {
+		l.Logger.Errorf("rename tag error: %v", err)
+		return nil, errorx.NewDefaultError("重命名标签失败")
+	}
+
+	return &types.TagResponse{ID: tag.ID, Name: tag.Name}, nil
+}
+
+// Merge 将多个标签合并到目标标签，源标签会被删除
+func (l *TagLogic) Merge(req *types.MergeTagsRequest) (*types.TagResponse, error) {
+	sourceIDs := make([]uint, 0, len(req.SourceIDs))
+	for _, id := range req.SourceIDs {
+		if id != 0 && id != req.TargetID {
+			sourceIDs = append(sourceIDs, id)
+		}
+	}
+	if len(sourceIDs) == 0 {
+		return nil, errorx.NewParamError("请选择要合并的标签")
+	}
+
+	var target model.Tag
+	if err := l.svcCtx.DB.First(&target, req.TargetID).Error; err != nil {
+		return nil, errorx.NewNotFoundError("目标标签不存在")
+	}
+
+	err := l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		// 1. 把源标签的文章挂到目标标签上（跳过已有目标标签的文章）
+		if err := tx.Exec(
+			"INSERT INTO article_tags (article_id, tag_id) "+
+				"SELECT DISTINCT article_id, ? FROM article_tags WHERE tag_id IN ? "+
+				"AND article_id NOT IN (SELECT article_id FROM article_tags WHERE tag_id = ?)",
+			target.ID, sourceIDs, target.ID,
+		).Error; err != nil {
+			return err
+		}
+
+		// 2. 删除源标签的关联和标签本身
+		if err := tx.Where("tag_id IN ?", sourceIDs).Delete(&model.ArticleTag{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("id IN ?", sourceIDs).Delete(&model.Tag{}).Error
+	})
+	if err != nil {
+		l.Logger.Errorf("merge tags error: %v", err)
+		return nil, errorx.NewDefaultError("合并标签失败")
+	}
+
+	return &types.TagResponse{ID: target.ID, Name: target.Name}, nil
+}
+
+// normalizeTagName 规范化标签名
+func normalizeTagName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errorx.NewParamError("标签名不能为空")
+	}
+	if utf8.RuneCountInString(name) > maxTagNameLength {
+		return "", errorx.NewParamError("标签名过长")
+	}
+	return name, nil
+}
+
+// resolveTags 按名称查找标签，不存在时创建
+func resolveTags(tx *gorm.DB, names []string) ([]model.Tag, error) {
+	seen := make(map[string]bool, len(names))
+	tags := make([]model.Tag, 0, len(names))
+	for _, raw := range names {
+		name, err := normalizeTagName(raw)
+		if err != nil {
+			return nil, err
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		var tag model.Tag
+		if err := tx.Where(model.Tag{Name: name}).FirstOrCreate(&tag).Error; err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	if len(tags) > maxArticleTags {
+		return nil, errorx.NewParamError("标签数量过多")
+	}
+	return tags, nil
+}
+
+// resolveCategories 按ID查找分类，任一不存在时报错
+func resolveCategories(tx *gorm.DB, ids []uint) ([]model.Category, error) {
+	var categories []model.Category
+	if len(ids) == 0 {
+		return categories, nil
+	}
+
+	unique := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		unique[id] = true
+	}
+
+	if err := tx.Where("id IN ?", ids).Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	if len(categories) != len(unique) {
+		return nil, errorx.NewParamError("分类不存在")
+	}
+	return categories, nil
+}
+
+func tagsToResponse(tags []model.Tag) []*types.TagResponse {
+	list := make([]*types.TagResponse, len(tags))
+	for i, tag := range tags {
+		list[i] = &types.TagResponse{ID: tag.ID, Name: tag.Name}
+	}
+	return list
+}
@@ -0,0 +1,244 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"acupofcoffee/api/internal/svc"
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/errorx"
+	"acupofcoffee/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type TrashLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewTrashLogic(ctx context.Context, svcCtx *svc.ServiceContext) *TrashLogic {
+	return &TrashLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// List 当前用户已删除的文章，包括作为共同作者的文章（与可以恢复的范围一致），最近删除的在前
+func (l *TrashLogic) List(req *types.PageRequest) (*types.PageResponse, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+
+	coAuthored := l.svcCtx.DB.Model(&model.ArticleCollaborator{}).Select("article_id").
+		Where("user_id = ? AND role = ? AND accepted_at IS NOT NULL", userID, model.CollaboratorCoAuthor)
+	query := l.svcCtx.DB.Unscoped().Model(&model.Article{}).
+		Where("(author_id = ? OR id IN (?)) AND deleted_at IS NOT NULL", userID, coAuthored)
+
+	var total int64
+	query.Count(&total)
+
+	var articles []model.Article
+	if err := query.Select("id", "title", "slug", "cover", "summary", "status", "deleted_at").
+		Order("deleted_at DESC, id DESC").
+		Scopes(model.Paginate(req.Page, req.PageSize)).
+		Find(&articles).Error; err != nil {
+		l.Logger.Errorf("list trash error: %v", err)
+		return nil, errorx.NewDefaultError("获取回收站失败")
+	}
+
+	retention := l.svcCtx.Config.Trash.Retention
+	list := make([]*types.TrashItem, len(articles))
+	for i, article := range articles {
+		deletedAt := article.DeletedAt.Time
+		list[i] = &types.TrashItem{
+			ID:        article.ID,
+			Title:     article.Title,
+			Slug:      article.Slug,
+			Cover:     article.Cover,
+			Summary:   article.Summary,
+			Status:    article.Status,
+			DeletedAt: deletedAt.Format("2006-01-02 15:04:05"),
+			PurgeAt:   deletedAt.Add(retention).Format("2006-01-02 15:04:05"),
+		}
+	}
+
+	return &types.PageResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		List:     list,
+	}, nil
+}
+
+// Restore 从回收站恢复文章，保持删除前的状态。
+// 文章用过的 slug 在删除期间仍保留在历史表中，不会被其他文章占用
+func (l *TrashLogic) Restore(id uint) error {
+	article, err := l.trashedArticle(id)
+	if err != nil {
+		return err
+	}
+
+	if err := restoreArticle(l.svcCtx.DB, article); err != nil {
+		l.Logger.Errorf("restore article error: %v", err)
+		return errorx.NewDefaultError("恢复文章失败")
+	}
+
+	return nil
+}
+
+// Purge 永久删除回收站中的文章
+func (l *TrashLogic) Purge(id uint) error {
+	article, err := l.trashedArticle(id)
+	if err != nil {
+		return err
+	}
+	// 共同作者可以恢复，但永久删除只能由作者本人或管理员操作
+	userID, _ := l.ctx.Value("userId").(uint)
+	if article.AuthorID != userID && !isAdmin(l.svcCtx.DB, userID) {
+		return errorx.NewForbiddenError("无权永久删除该文章")
+	}
+
+	if err := l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		return purgeArticle(tx, article.ID)
+	}); err != nil {
+		l.Logger.Errorf("purge article error: %v", err)
+		return errorx.NewDefaultError("永久删除文章失败")
+	}
+
+	return nil
+}
+
+// PurgeExpired 永久删除超过保留期的文章，由定时任务调用
+func (l *TrashLogic) PurgeExpired() error {
+	cfg := l.svcCtx.Config.Trash
+	if cfg.Retention <= 0 {
+		return nil
+	}
+	before := time.Now().Add(-cfg.Retention)
+
+	var purged int
+	for {
+		var ids []uint
+		if err := l.svcCtx.DB.Unscoped().Model(&model.Article{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+			Order("id").
+			Limit(cfg.BatchSize).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+
+		failed := 0
+		for _, id := range ids {
+			if err := l.ctx.Err(); err != nil {
+				return err
+			}
+			// 每篇文章单独一个事务，一篇失败不影响其他文章
+			if err := l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+				return purgeArticle(tx, id)
+			}); err != nil {
+				l.Logger.Errorf("purge article %d error: %v", id, err)
+				failed++
+				continue
+			}
+			purged++
+		}
+
+		// 不足一批说明已处理完；整批都失败时留到下次，避免反复处理同一批
+		if len(ids) < cfg.BatchSize || failed == len(ids) {
+			break
+		}
+	}
+
+	if purged > 0 {
+		l.Logger.Infof("purged %d expired articles from trash", purged)
+	}
+	return nil
+}
+
+// trashedArticle 读取回收站中的文章，只有作者、共同作者和管理员可以操作
+func (l *TrashLogic) trashedArticle(id uint) (*model.Article, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+
+	var article model.Article
+	err := l.svcCtx.DB.Unscoped().
+		Where("deleted_at IS NOT NULL").
+		First(&article, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errorx.NewNotFoundError("回收站中没有该文章")
+	}
+	if err != nil {
+		l.Logger.Errorf("get trashed article error: %v", err)
+		return nil, errorx.NewDefaultError("获取文章失败")
+	}
+
+	if !isAuthorOrAdmin(l.svcCtx.DB, &article, userID) {
+		return nil, errorx.NewForbiddenError("无权操作该文章")
+	}
+
+	return &article, nil
+}
+
+// trashArticle 软删除文章，同时清理所有人对该文章的草稿
+func trashArticle(tx *gorm.DB, article *model.Article) error {
+	if err := deleteArticleDrafts(tx, article.ID, 0); err != nil {
+		return err
+	}
+	return tx.Delete(article).Error
+}
+
+// restoreArticle 取消软删除，不更新修改时间，恢复不算对内容的修改；
+// 单独记录恢复时间，已发布的文章重新出现在订阅源中时据此更新订阅源的修改时间
+func restoreArticle(tx *gorm.DB, article *model.Article) error {
+	now := time.Now()
+	if err := tx.Unscoped().Model(article).UpdateColumns(map[string]interface{}{
+		"deleted_at":  nil,
+		"restored_at": &now,
+	}).Error; err != nil {
+		return err
+	}
+	article.DeletedAt = gorm.DeletedAt{}
+	article.RestoredAt = &now
+	return nil
+}
+
+// purgeArticle 永久删除文章及所有关联数据，上传的文件只解除引用，仍保留在作者的文件库中
+func purgeArticle(tx *gorm.DB, articleID uint) error {
+	if err := deleteArticleDrafts(tx, articleID, 0); err != nil {
+		return err
+	}
+
+	related := []interface{}{
+		&model.ArticleVersion{},
+		&model.ArticleSlug{},
+		&model.ArticleTransition{},
+		&model.Comment{},
+		&model.Notification{},
+		&model.ArticleLike{},
+		&model.ArticleReaction{},
+		&model.Bookmark{},
+		&model.SeriesArticle{},
+		&model.ArticleTag{},
+		&model.ArticleCategory{},
+		&model.MediaReference{},
+		&model.ArticleDailyStat{},
+		&model.ArticleDailyReferrer{},
+		&model.ArticleDailyVisitor{},
+		&model.ArticleCollaborator{},
+	}
+	for _, m := range related {
+		if err := tx.Unscoped().Where("article_id = ?", articleID).Delete(m).Error; err != nil {
+			return err
+		}
+	}
+
+	return tx.Unscoped().Delete(&model.Article{}, articleID).Error
+}
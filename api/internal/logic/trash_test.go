@@ -0,0 +1,73 @@
+package logic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/model"
+)
+
+func TestTrashListIncludesCoAuthoredArticles(t *testing.T) {
+	svcCtx := newTestServiceContext(t)
+	a, err := NewArticleLogic(userContext(1), svcCtx).Create(&types.CreateArticleRequest{Title: "Title", Content: "content"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	now := time.Now()
+	for _, c := range []model.ArticleCollaborator{
+		{ArticleID: a.ID, UserID: 2, Role: model.CollaboratorCoAuthor, InvitedBy: 1, AcceptedAt: &now},
+		{ArticleID: a.ID, UserID: 3, Role: model.CollaboratorEditor, InvitedBy: 1, AcceptedAt: &now},
+		{ArticleID: a.ID, UserID: 4, Role: model.CollaboratorCoAuthor, InvitedBy: 1},
+	} {
+		if err := svcCtx.DB.Create(&c).Error; err != nil {
+			t.Fatalf("add collaborator: %v", err)
+		}
+	}
+	if err := NewArticleLogic(userContext(1), svcCtx).Delete(a.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	// 作者和已接受邀请的共同作者可以恢复，也就能在回收站中看到；编辑和未接受邀请的看不到
+	for userID, want := range map[uint]int64{1: 1, 2: 1, 3: 0, 4: 0} {
+		resp, err := NewTrashLogic(userContext(userID), svcCtx).List(&types.PageRequest{Page: 1, PageSize: 10})
+		if err != nil {
+			t.Fatalf("list trash of user %d: %v", userID, err)
+		}
+		if resp.Total != want {
+			t.Errorf("user %d sees %d trashed articles, want %d", userID, resp.Total, want)
+		}
+	}
+}
+
+func TestRestoreMovesFeedForward(t *testing.T) {
+	svcCtx := newTestServiceContext(t)
+	a, err := NewArticleLogic(userContext(1), svcCtx).Create(&types.CreateArticleRequest{Title: "Title", Content: "content", Status: model.ArticleStatusPublished})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := NewArticleLogic(userContext(1), svcCtx).Delete(a.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	feed, err := NewSyndicationLogic(context.Background(), svcCtx).Feed(&types.SyndicationRequest{}, "/feed.xml")
+	if err != nil {
+		t.Fatalf("feed: %v", err)
+	}
+	trashedAt := feed.Updated
+
+	time.Sleep(10 * time.Millisecond)
+	if err := NewTrashLogic(userContext(1), svcCtx).Restore(a.ID); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	feed, err = NewSyndicationLogic(context.Background(), svcCtx).Feed(&types.SyndicationRequest{}, "/feed.xml")
+	if err != nil {
+		t.Fatalf("feed: %v", err)
+	}
+	if len(feed.Items) != 1 {
+		t.Fatalf("feed has %d items, want 1", len(feed.Items))
+	}
+	if !feed.Updated.After(trashedAt) {
+		t.Fatalf("feed updated %v not after trash time %v", feed.Updated, trashedAt)
+	}
+}
@@ -0,0 +1,146 @@
+package logic
+
+import (
+	"strconv"
+	"strings"
+
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/errorx"
+	"acupofcoffee/common/richtext"
+	"acupofcoffee/model"
+)
+
+// versionCurrent 比较时表示文章当前内容
+const versionCurrent = "current"
+
+// versionSnapshot 参与比较的一个版本
+type versionSnapshot struct {
+	ref     *types.VersionRef
+	title   string
+	content string
+}
+
+// CompareVersions 比较文章的两个版本，a、b 为版本号或 current，可见范围与版本历史相同
+func (l *ArticleLogic) CompareVersions(req *types.CompareVersionsRequest) (*types.VersionDiffResponse, error) {
+	article, err := l.viewableArticle(req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := l.loadVersionSnapshot(article, req.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := l.loadVersionSnapshot(article, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &types.VersionDiffResponse{
+		ArticleID: article.ID,
+		From:      from.ref,
+		To:        to.ref,
+		Title:     inlineDiffToResponse(richtext.DiffText(from.title, to.title)),
+	}
+	resp.TitleChanged = from.title != to.title
+
+	oldBlocks := richtext.Parse(from.content)
+	newBlocks := richtext.Parse(to.content)
+
+	changes := richtext.DiffBlocks(oldBlocks, newBlocks)
+	resp.Blocks = make([]*types.BlockDiff, len(changes))
+	for i, change := range changes {
+		item := &types.BlockDiff{Op: change.Op}
+		switch change.Op {
+		case richtext.OpInsert:
+			item.Type, item.Level, item.NewText = change.New.Type, change.New.Level, change.New.Text
+			resp.Stats.BlocksAdded++
+			resp.Stats.CharsAdded += len([]rune(change.New.Text))
+		case richtext.OpDelete:
+			item.Type, item.Level, item.OldText = change.Old.Type, change.Old.Level, change.Old.Text
+			resp.Stats.BlocksRemoved++
+			resp.Stats.CharsRemoved += len([]rune(change.Old.Text))
+		case richtext.OpModify:
+			item.Type, item.Level = change.New.Type, change.New.Level
+			item.OldText, item.NewText = change.Old.Text, change.New.Text
+			item.Inline = inlineDiffToResponse(change.Inline)
+			added, removed := richtext.ChangedChars(change.Inline)
+			resp.Stats.BlocksModified++
+			resp.Stats.CharsAdded += added
+			resp.Stats.CharsRemoved += removed
+		default:
+			item.Type, item.Level, item.NewText = change.New.Type, change.New.Level, change.New.Text
+		}
+		resp.Blocks[i] = item
+	}
+
+	// 统一格式的文本差异，第一行为标题
+	oldText := append([]richtext.Block{{Type: richtext.BlockHeading, Level: 1, Text: from.title}}, oldBlocks...)
+	newText := append([]richtext.Block{{Type: richtext.BlockHeading, Level: 1, Text: to.title}}, newBlocks...)
+	resp.Unified = richtext.UnifiedDiff(oldText, newText, versionLabel(from.ref), versionLabel(to.ref))
+
+	return resp, nil
+}
+
+// loadVersionSnapshot 按版本号加载历史版本，current 或当前版本号取文章本身
+func (l *ArticleLogic) loadVersionSnapshot(article *model.Article, target string) (*versionSnapshot, error) {
+	target = strings.ToLower(strings.TrimSpace(target))
+
+	number := article.Version
+	if target != versionCurrent {
+		n, err := strconv.Atoi(target)
+		if err != nil || n <= 0 {
+			return nil, errorx.NewParamError("版本号格式错误")
+		}
+		number = n
+	}
+
+	if number == article.Version {
+		return &versionSnapshot{
+			ref: &types.VersionRef{
+				Version:   article.Version,
+				Current:   true,
+				Title:     article.Title,
+				CreatedAt: article.UpdatedAt.Format("2006-01-02 15:04:05"),
+			},
+			title:   article.Title,
+			content: article.Content,
+		}, nil
+	}
+
+	var version model.ArticleVersion
+	if err := l.svcCtx.DB.Where("article_id = ? AND version = ?", article.ID, number).
+		First(&version).Error; err != nil {
+		return nil, errorx.NewNotFoundError("版本不存在")
+	}
+	if err := materializeVersion(l.svcCtx.DB, &version); err != nil {
+		l.Logger.Errorf("restore version %d error: %v", version.ID, err)
+		return nil, errorx.NewDefaultError("版本数据损坏")
+	}
+
+	return &versionSnapshot{
+		ref: &types.VersionRef{
+			Version:   version.Version,
+			Title:     version.Title,
+			Remark:    version.Remark,
+			CreatedAt: version.CreatedAt.Format("2006-01-02 15:04:05"),
+		},
+		title:   version.Title,
+		content: version.Content,
+	}, nil
+}
+
+func versionLabel(ref *types.VersionRef) string {
+	if ref.Current {
+		return "v" + strconv.Itoa(ref.Version) + " (current)"
+	}
+	return "v" + strconv.Itoa(ref.Version)
+}
+
+func inlineDiffToResponse(segments []richtext.Segment) []*types.InlineDiff {
+	list := make([]*types.InlineDiff, len(segments))
+	for i, s := range segments {
+		list[i] = &types.InlineDiff{Op: s.Op, Text: s.Text}
+	}
+	return list
+}
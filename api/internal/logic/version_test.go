@@ -0,0 +1,134 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/errorx"
+	"acupofcoffee/model"
+)
+
+func TestRestoreVersionRejectsOtherArticle(t *testing.T) {
+	svcCtx := newTestServiceContext(t)
+	l := NewArticleLogic(userContext(1), svcCtx)
+
+	a, err := l.Create(&types.CreateArticleRequest{Title: "Article A", Content: "content a"})
+	if err != nil {
+		t.Fatalf("create a: %v", err)
+	}
+	b, err := l.Create(&types.CreateArticleRequest{Title: "Article B", Content: "content b"})
+	if err != nil {
+		t.Fatalf("create b: %v", err)
+	}
+	if _, err := l.Update(&types.UpdateArticleRequest{ID: a.ID, Content: "content a2"}); err != nil {
+		t.Fatalf("update a: %v", err)
+	}
+
+	var versionOfA model.ArticleVersion
+	if err := svcCtx.DB.Where("article_id = ?", a.ID).First(&versionOfA).Error; err != nil {
+		t.Fatalf("load version: %v", err)
+	}
+
+	_, err = l.RestoreVersion(b.ID, versionOfA.ID)
+	var codeErr *errorx.CodeError
+	if !errors.As(err, &codeErr) || (codeErr.Code != errorx.CodeNotFound && codeErr.Code != errorx.CodeParamError) {
+		t.Fatalf("restore version of another article: got %v, want not found or param error", err)
+	}
+
+	var after model.Article
+	svcCtx.DB.First(&after, b.ID)
+	if after.Title != "Article B" || after.Content != "content b" || after.Version != b.Version {
+		t.Fatalf("article b changed: title=%q content=%q version=%d", after.Title, after.Content, after.Version)
+	}
+	var count int64
+	svcCtx.DB.Model(&model.ArticleVersion{}).Where("article_id = ?", b.ID).Count(&count)
+	if count != 0 {
+		t.Fatalf("article b has %d versions, want 0", count)
+	}
+}
+
+func TestRestoreVersionRecordsSource(t *testing.T) {
+	svcCtx := newTestServiceContext(t)
+	l := NewArticleLogic(userContext(1), svcCtx)
+
+	a, err := l.Create(&types.CreateArticleRequest{Title: "Title v1", Content: "content v1"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := l.Update(&types.UpdateArticleRequest{ID: a.ID, Title: "Title v2", Content: "content v2"}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	var v1 model.ArticleVersion
+	if err := svcCtx.DB.Where("article_id = ? AND version = ?", a.ID, 1).First(&v1).Error; err != nil {
+		t.Fatalf("load v1: %v", err)
+	}
+
+	resp, err := l.RestoreVersion(a.ID, v1.ID)
+	if err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if resp.Title != "Title v1" || resp.Content != "content v1" || resp.Version != 3 {
+		t.Fatalf("restored article: title=%q content=%q version=%d", resp.Title, resp.Content, resp.Version)
+	}
+
+	if resp.RestoredFrom != 1 {
+		t.Fatalf("restored article: restoredFrom = %d, want 1", resp.RestoredFrom)
+	}
+
+	// 恢复前的 v2 内容照常保存为历史版本，不带恢复来源
+	versions, err := l.GetVersions(a.ID)
+	if err != nil {
+		t.Fatalf("get versions: %v", err)
+	}
+	if len(versions) != 2 {
+		t.Fatalf("got %d versions, want 2", len(versions))
+	}
+	if v2 := versions[0]; v2.Version != 2 || v2.Content != "content v2" || v2.RestoredFrom != 0 || v2.Remark != "" {
+		t.Fatalf("v2: version=%d content=%q restoredFrom=%d remark=%q", v2.Version, v2.Content, v2.RestoredFrom, v2.Remark)
+	}
+
+	// 再次编辑后，恢复得到的 v3 内容存为历史版本并指向来源
+	resp, err = l.Update(&types.UpdateArticleRequest{ID: a.ID, Content: "content v4", Remark: "补充"})
+	if err != nil {
+		t.Fatalf("update after restore: %v", err)
+	}
+	if resp.RestoredFrom != 0 {
+		t.Fatalf("edited article: restoredFrom = %d, want 0", resp.RestoredFrom)
+	}
+	if versions, err = l.GetVersions(a.ID); err != nil {
+		t.Fatalf("get versions: %v", err)
+	}
+	v3 := versions[0]
+	if v3.Version != 3 || v3.Title != "Title v1" || v3.Content != "content v1" {
+		t.Fatalf("v3: version=%d title=%q content=%q", v3.Version, v3.Title, v3.Content)
+	}
+	if v3.RestoredFrom != 1 || v3.Remark != "从 v1 恢复；补充" {
+		t.Fatalf("v3: restoredFrom=%d remark=%q, want 1 and %q", v3.RestoredFrom, v3.Remark, "从 v1 恢复；补充")
+	}
+}
+
+func TestCompareVersionsHiddenForUnpublished(t *testing.T) {
+	svcCtx := newTestServiceContext(t)
+	l := NewArticleLogic(userContext(1), svcCtx)
+
+	a, err := l.Create(&types.CreateArticleRequest{Title: "Draft", Content: "secret v1"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := l.Update(&types.UpdateArticleRequest{ID: a.ID, Content: "secret v2"}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	req := &types.CompareVersionsRequest{ID: a.ID, From: "1", To: versionCurrent}
+	_, err = NewArticleLogic(context.Background(), svcCtx).CompareVersions(req)
+	var codeErr *errorx.CodeError
+	if !errors.As(err, &codeErr) || codeErr.Code != errorx.CodeNotFound {
+		t.Fatalf("anonymous compare: got %v, want not found", err)
+	}
+	if _, err := l.CompareVersions(req); err != nil {
+		t.Fatalf("author compare: %v", err)
+	}
+}
@@ -0,0 +1,332 @@
+package logic
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	"acupofcoffee/api/internal/config"
+	"acupofcoffee/api/internal/svc"
+	"acupofcoffee/common/utils"
+	"acupofcoffee/model"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+var errBrokenVersionChain = errors.New("version chain has no snapshot")
+
+// saveArticleVersion 将文章当前内容存为历史版本，当前内容由恢复得到时一并记录恢复来源。
+// 距上一个快照不足 SnapshotInterval 个版本时只存相对上一个版本的压缩增量。
+func saveArticleVersion(tx *gorm.DB, cfg config.VersionConfig, article *model.Article, remark string) error {
+	if article.RestoredFrom > 0 {
+		restored := restoreRemark(article.RestoredFrom)
+		if remark == "" {
+			remark = restored
+		} else {
+			remark = restored + "；" + remark
+		}
+	}
+
+	status := article.Status
+	version := model.ArticleVersion{
+		ArticleID:        article.ID,
+		Title:            article.Title,
+		Content:          article.Content,
+		Version:          article.Version,
+		Remark:           remark,
+		Cover:            article.Cover,
+		Summary:          article.Summary,
+		SummaryGenerated: article.SummaryGenerated,
+		Status:           &status,
+		RestoredFrom:     article.RestoredFrom,
+		EditorID:         article.EditorID,
+		Kind:             model.ArticleVersionSnapshot,
+	}
+
+	chain, err := loadVersionChain(tx, article.ID, 0)
+	if err != nil {
+		return err
+	}
+	if len(chain) > 0 && len(chain) < cfg.SnapshotInterval {
+		contents, err := restoreVersionContents(chain)
+		if err != nil {
+			return err
+		}
+		encodeVersion(&version, contents[len(contents)-1], article.Content)
+	}
+
+	return tx.Create(&version).Error
+}
+
+// restoreRemark 恢复得到的版本的备注
+func restoreRemark(from int) string {
+	return "从 v" + strconv.Itoa(from) + " 恢复"
+}
+
+// materializeVersion 还原增量版本的完整内容
+func materializeVersion(db *gorm.DB, version *model.ArticleVersion) error {
+	if version.Kind == model.ArticleVersionSnapshot {
+		return nil
+	}
+
+	chain, err := loadVersionChain(db, version.ArticleID, version.Version)
+	if err != nil {
+		return err
+	}
+	contents, err := restoreVersionContents(chain)
+	if err != nil {
+		return err
+	}
+	if len(contents) == 0 {
+		return errBrokenVersionChain
+	}
+
+	version.Content = contents[len(contents)-1]
+	return nil
+}
+
+// loadVersionChain 加载从最近一个快照到指定版本（0 表示最新）的版本记录
+func loadVersionChain(db *gorm.DB, articleID uint, upto int) ([]model.ArticleVersion, error) {
+	var snapshot model.ArticleVersion
+	query := db.Where("article_id = ? AND kind = ?", articleID, model.ArticleVersionSnapshot)
+	if upto > 0 {
+		query = query.Where("version <= ?", upto)
+	}
+	err := query.Order("version DESC").First(&snapshot).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rest []model.ArticleVersion
+	query = db.Where("article_id = ? AND version > ?", articleID, snapshot.Version)
+	if upto > 0 {
+		query = query.Where("version <= ?", upto)
+	}
+	if err := query.Order("version ASC").Find(&rest).Error; err != nil {
+		return nil, err
+	}
+
+	return append([]model.ArticleVersion{snapshot}, rest...), nil
+}
+
+// restoreVersionContents 按版本顺序依次还原内容，第一个记录必须是快照
+func restoreVersionContents(versions []model.ArticleVersion) ([]string, error) {
+	contents := make([]string, len(versions))
+	for i, v := range versions {
+		if v.Kind == model.ArticleVersionSnapshot {
+			contents[i] = v.Content
+			continue
+		}
+		if i == 0 {
+			return nil, errBrokenVersionChain
+		}
+
+		content, err := applyContentDelta(contents[i-1], v.Delta)
+		if err != nil {
+			return nil, err
+		}
+		contents[i] = content
+	}
+	return contents, nil
+}
+
+// encodeVersion 以增量方式存储版本，增量不比全文小时仍存快照
+func encodeVersion(version *model.ArticleVersion, base, content string) {
+	version.Kind = model.ArticleVersionSnapshot
+	version.Content = content
+	version.Delta = nil
+
+	delta, err := makeContentDelta(base, content)
+	if err != nil || len(delta) >= len(content) {
+		return
+	}
+
+	version.Kind = model.ArticleVersionDelta
+	version.Content = ""
+	version.Delta = delta
+}
+
+func makeContentDelta(base, content string) ([]byte, error) {
+	dmp := diffmatchpatch.New()
+	diffs := dmp.DiffMain(base, content, true)
+
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write([]byte(dmp.DiffToDelta(diffs))); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func applyContentDelta(base string, delta []byte) (string, error) {
+	r := flate.NewReader(bytes.NewReader(delta))
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+
+	dmp := diffmatchpatch.New()
+	diffs, err := dmp.DiffFromDelta(base, string(data))
+	if err != nil {
+		return "", err
+	}
+	return dmp.DiffText2(diffs), nil
+}
+
+type VersionLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewVersionLogic(ctx context.Context, svcCtx *svc.ServiceContext) *VersionLogic {
+	return &VersionLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// Compact 按保留策略清理历史版本，由后台任务周期调用。
+// KeepAllDays 天内的版本全部保留，更早的每篇文章每天只保留最后一个版本，有备注的版本始终保留。
+func (l *VersionLogic) Compact() error {
+	cfg := l.svcCtx.Config.Version
+	if cfg.KeepAllDays <= 0 {
+		return nil
+	}
+	cutoff := utils.GetStartOfDay(time.Now()).AddDate(0, 0, -cfg.KeepAllDays)
+
+	var articleIDs []uint
+	if err := l.svcCtx.DB.Model(&model.ArticleVersion{}).
+		Where("created_at < ?", cutoff).
+		Distinct().
+		Pluck("article_id", &articleIDs).Error; err != nil {
+		return err
+	}
+
+	var removed int
+	for _, id := range articleIDs {
+		n, err := l.compactArticle(id, cutoff)
+		if err != nil {
+			l.Logger.Errorf("compact versions of article %d error: %v", id, err)
+			continue
+		}
+		removed += n
+	}
+
+	if removed > 0 {
+		l.Logger.Infof("version compaction: %d versions removed", removed)
+	}
+	return nil
+}
+
+// compactArticle 清理单篇文章的历史版本，被删除版本之后的增量会基于保留的上一个版本重新编码
+func (l *VersionLogic) compactArticle(articleID uint, cutoff time.Time) (int, error) {
+	cfg := l.svcCtx.Config.Version
+	removed := 0
+
+	err := l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		var versions []model.ArticleVersion
+		if err := tx.Where("article_id = ?", articleID).Order("version ASC").Find(&versions).Error; err != nil {
+			return err
+		}
+		contents, err := restoreVersionContents(versions)
+		if err != nil {
+			return err
+		}
+
+		// 同一天的旧版本只保留最后一个
+		keep := make([]bool, len(versions))
+		for i, v := range versions {
+			if !v.CreatedAt.Before(cutoff) || v.Remark != "" {
+				keep[i] = true
+				continue
+			}
+			last := i == len(versions)-1 ||
+				!utils.GetStartOfDay(versions[i+1].CreatedAt).Equal(utils.GetStartOfDay(v.CreatedAt))
+			keep[i] = last
+		}
+
+		var deleteIDs []uint
+		for i, v := range versions {
+			if !keep[i] {
+				deleteIDs = append(deleteIDs, v.ID)
+			}
+		}
+		if len(deleteIDs) == 0 {
+			return nil
+		}
+		// 物理删除才能真正释放空间
+		if err := tx.Unscoped().Delete(&model.ArticleVersion{}, deleteIDs).Error; err != nil {
+			return err
+		}
+		removed = len(deleteIDs)
+
+		// 重新整理保留版本的快照/增量链
+		prev, chainLen := -1, 0
+		for i := range versions {
+			if !keep[i] {
+				continue
+			}
+			v := &versions[i]
+
+			switch {
+			case v.Kind == model.ArticleVersionSnapshot:
+			case prev == i-1:
+				// 基准版本未被删除，增量仍然有效
+			case prev < 0 || chainLen >= cfg.SnapshotInterval:
+				if err := rewriteVersion(tx, v, "", contents[i], true); err != nil {
+					return err
+				}
+			default:
+				if err := rewriteVersion(tx, v, contents[prev], contents[i], false); err != nil {
+					return err
+				}
+			}
+
+			if v.Kind == model.ArticleVersionSnapshot {
+				chainLen = 1
+			} else {
+				chainLen++
+			}
+			prev = i
+		}
+
+		return nil
+	})
+
+	return removed, err
+}
+
+func rewriteVersion(tx *gorm.DB, version *model.ArticleVersion, base, content string, snapshot bool) error {
+	if snapshot {
+		version.Kind = model.ArticleVersionSnapshot
+		version.Content = content
+		version.Delta = nil
+	} else {
+		encodeVersion(version, base, content)
+	}
+
+	return tx.Model(version).Updates(map[string]interface{}{
+		"kind":    version.Kind,
+		"content": version.Content,
+		"delta":   version.Delta,
+	}).Error
+}
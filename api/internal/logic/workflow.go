@@ -0,0 +1,331 @@
+package logic
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"acupofcoffee/api/internal/config"
+	"acupofcoffee/api/internal/svc"
+	"acupofcoffee/api/internal/types"
+	"acupofcoffee/common/errorx"
+	"acupofcoffee/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+// 状态流转的操作者
+const (
+	actorAuthor   = 1 << iota // 作者
+	actorReviewer             // 审核员/管理员
+)
+
+// workflowRules 合法的状态流转及允许的操作者，未列出的流转一律拒绝
+var workflowRules = map[int8]map[int8]int{
+	model.ArticleStatusDraft: {
+		model.ArticleStatusInReview:  actorAuthor,
+		model.ArticleStatusPublished: actorAuthor | actorReviewer, // 开启强制审核后仅审核员
+		model.ArticleStatusArchived:  actorAuthor,
+	},
+	model.ArticleStatusInReview: {
+		model.ArticleStatusApproved:         actorReviewer,
+		model.ArticleStatusChangesRequested: actorReviewer,
+		model.ArticleStatusDraft:            actorAuthor, // 撤回
+	},
+	model.ArticleStatusChangesRequested: {
+		model.ArticleStatusInReview: actorAuthor,
+		model.ArticleStatusDraft:    actorAuthor,
+	},
+	model.ArticleStatusApproved: {
+		model.ArticleStatusPublished: actorAuthor | actorReviewer,
+		model.ArticleStatusDraft:     actorAuthor,
+	},
+	model.ArticleStatusPublished: {
+		model.ArticleStatusDraft:    actorAuthor,
+		model.ArticleStatusArchived: actorAuthor | actorReviewer,
+	},
+	model.ArticleStatusArchived: {
+		model.ArticleStatusDraft:     actorAuthor,
+		model.ArticleStatusPublished: actorAuthor | actorReviewer, // 同草稿
+	},
+}
+
+// checkTransition 校验状态流转是否合法，actors 为当前操作者具备的身份
+func checkTransition(cfg config.WorkflowConfig, from, to int8, actors int) error {
+	allowed, ok := workflowRules[from][to]
+	if !ok {
+		return errorx.NewParamError("不允许的状态变更")
+	}
+
+	// 强制审核时，作者不能跳过审核直接发布
+	if cfg.RequireReview && to == model.ArticleStatusPublished && from != model.ArticleStatusApproved {
+		allowed &^= actorAuthor
+	}
+
+	if allowed&actors == 0 {
+		return errorx.NewForbiddenError("无权执行该状态变更")
+	}
+	return nil
+}
+
+// statusAfterEdit 修改内容后文章应处于的状态及流转说明。
+// 审核通过后修改内容需要重新审核；开启强制审核时，审核中的文章不能修改内容，
+// 已发布的文章被作者修改后回到审核中，审核员和管理员的修改直接生效
+func statusAfterEdit(cfg config.WorkflowConfig, status int8, actors int) (int8, string, error) {
+	if status == model.ArticleStatusApproved {
+		return model.ArticleStatusDraft, "审核通过后修改了内容", nil
+	}
+	if !cfg.RequireReview || actors&actorReviewer != 0 {
+		return status, "", nil
+	}
+
+	switch status {
+	case model.ArticleStatusInReview:
+		return status, "", errorx.NewParamError("文章审核中，请撤回后再修改")
+	case model.ArticleStatusPublished:
+		return model.ArticleStatusInReview, "发布后修改了内容，重新审核", nil
+	}
+	return status, "", nil
+}
+
+// transitionArticle 在事务中变更文章状态并记录流转
+func transitionArticle(tx *gorm.DB, article *model.Article, to int8, actorID uint, comment string) error {
+	record := model.ArticleTransition{
+		ArticleID:  article.ID,
+		FromStatus: article.Status,
+		ToStatus:   to,
+		ActorID:    actorID,
+		Comment:    comment,
+	}
+	if err := tx.Create(&record).Error; err != nil {
+		return err
+	}
+
+	updates := map[string]interface{}{"status": to}
+	// 只记录首次发布时间，下线后重新发布不会让文章重新出现在关注者的动态中
+	if to == model.ArticleStatusPublished && article.PublishedAt == nil {
+		now := time.Now()
+		updates["published_at"] = &now
+		article.PublishedAt = &now
+	}
+
+	if err := tx.Model(article).Updates(updates).Error; err != nil {
+		return err
+	}
+	article.Status = to
+	return nil
+}
+
+// articleActors 计算用户对文章具备的身份
+func articleActors(db *gorm.DB, article *model.Article, userID uint) int {
+	actors := 0
+	// 共同作者与作者同等，编辑和只读协作者不能变更状态
+	author := isArticleAuthor(db, article, userID)
+	if author {
+		actors |= actorAuthor
+	}
+
+	var user model.User
+	if userID != 0 && db.Select("id", "role").First(&user, userID).Error == nil && user.IsReviewer() {
+		// 审核员不能审核自己参与署名的文章，管理员除外
+		if !author || user.IsAdmin() {
+			actors |= actorReviewer
+		}
+	}
+
+	return actors
+}
+
+type WorkflowLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewWorkflowLogic(ctx context.Context, svcCtx *svc.ServiceContext) *WorkflowLogic {
+	return &WorkflowLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// Submit 作者提交审核
+func (l *WorkflowLogic) Submit(req *types.ArticleReviewRequest) (*types.ArticleTransitionResponse, error) {
+	return l.transition(req.ID, model.ArticleStatusInReview, req.Comment)
+}
+
+// Withdraw 作者撤回审核
+func (l *WorkflowLogic) Withdraw(req *types.ArticleReviewRequest) (*types.ArticleTransitionResponse, error) {
+	return l.transition(req.ID, model.ArticleStatusDraft, req.Comment)
+}
+
+// Approve 审核通过
+func (l *WorkflowLogic) Approve(req *types.ArticleReviewRequest) (*types.ArticleTransitionResponse, error) {
+	return l.transition(req.ID, model.ArticleStatusApproved, req.Comment)
+}
+
+// Reject 驳回修改，必须填写意见
+func (l *WorkflowLogic) Reject(req *types.ArticleReviewRequest) (*types.ArticleTransitionResponse, error) {
+	if strings.TrimSpace(req.Comment) == "" {
+		return nil, errorx.NewParamError("请填写修改意见")
+	}
+	return l.transition(req.ID, model.ArticleStatusChangesRequested, req.Comment)
+}
+
+// ListTransitions 文章状态流转历史，仅作者和审核员可见
+func (l *WorkflowLogic) ListTransitions(articleID uint) ([]*types.ArticleTransitionResponse, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+
+	var article model.Article
+	if err := l.svcCtx.DB.First(&article, articleID).Error; err != nil {
+		return nil, errorx.NewNotFoundError("文章不存在")
+	}
+	if articleActors(l.svcCtx.DB, &article, userID) == 0 {
+		return nil, errorx.NewForbiddenError("无权查看")
+	}
+
+	var records []model.ArticleTransition
+	if err := l.svcCtx.DB.Preload("Actor").
+		Where("article_id = ?", articleID).
+		Order("id DESC").
+		Find(&records).Error; err != nil {
+		return nil, errorx.NewDefaultError("获取流转记录失败")
+	}
+
+	list := make([]*types.ArticleTransitionResponse, len(records))
+	for i := range records {
+		list[i] = transitionToResponse(&records[i])
+	}
+	return list, nil
+}
+
+// ReviewQueue 待审核文章队列，按提交时间先后排序
+func (l *WorkflowLogic) ReviewQueue(req *types.PageRequest) (*types.PageResponse, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+
+	var user model.User
+	if err := l.svcCtx.DB.First(&user, userID).Error; err != nil || !user.IsReviewer() {
+		return nil, errorx.NewForbiddenError("需要审核权限")
+	}
+
+	query := l.svcCtx.DB.Model(&model.Article{}).Where("status = ?", model.ArticleStatusInReview)
+
+	var total int64
+	query.Count(&total)
+
+	var articles []model.Article
+	if err := query.Preload("Author").
+		Order("updated_at ASC, id ASC").
+		Scopes(model.Paginate(req.Page, req.PageSize)).
+		Find(&articles).Error; err != nil {
+		l.Logger.Errorf("review queue error: %v", err)
+		return nil, errorx.NewDefaultError("获取审核队列失败")
+	}
+
+	// 最近一次提交审核的记录
+	ids := make([]uint, len(articles))
+	for i, article := range articles {
+		ids[i] = article.ID
+	}
+	submissions := make(map[uint]*model.ArticleTransition, len(ids))
+	if len(ids) > 0 {
+		var records []model.ArticleTransition
+		l.svcCtx.DB.Where("article_id IN ? AND to_status = ?", ids, model.ArticleStatusInReview).
+			Order("id ASC").
+			Find(&records)
+		for i := range records {
+			submissions[records[i].ArticleID] = &records[i]
+		}
+	}
+
+	list := make([]*types.ReviewQueueItem, len(articles))
+	for i, article := range articles {
+		item := &types.ReviewQueueItem{
+			ArticleID: article.ID,
+			Title:     article.Title,
+			Summary:   article.Summary,
+			AuthorID:  article.AuthorID,
+			Version:   article.Version,
+		}
+		if article.Author != nil {
+			item.AuthorName = article.Author.Nickname
+			if item.AuthorName == "" {
+				item.AuthorName = article.Author.Username
+			}
+		}
+		if record, ok := submissions[article.ID]; ok {
+			item.SubmittedAt = record.CreatedAt.Format("2006-01-02 15:04:05")
+			item.Comment = record.Comment
+		}
+		list[i] = item
+	}
+
+	return &types.PageResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		List:     list,
+	}, nil
+}
+
+func (l *WorkflowLogic) transition(articleID uint, to int8, comment string) (*types.ArticleTransitionResponse, error) {
+	userID, ok := l.ctx.Value("userId").(uint)
+	if !ok {
+		return nil, errorx.NewUnauthorizedError("未登录")
+	}
+
+	var article model.Article
+	if err := l.svcCtx.DB.First(&article, articleID).Error; err != nil {
+		return nil, errorx.NewNotFoundError("文章不存在")
+	}
+
+	actors := articleActors(l.svcCtx.DB, &article, userID)
+	if err := checkTransition(l.svcCtx.Config.Workflow, article.Status, to, actors); err != nil {
+		return nil, err
+	}
+
+	from := article.Status
+	if err := l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		return transitionArticle(tx, &article, to, userID, comment)
+	}); err != nil {
+		l.Logger.Errorf("article transition error: %v", err)
+		return nil, errorx.NewDefaultError("状态变更失败")
+	}
+
+	return &types.ArticleTransitionResponse{
+		ArticleID:  article.ID,
+		FromStatus: from,
+		ToStatus:   to,
+		ActorID:    userID,
+		Comment:    comment,
+	}, nil
+}
+
+func transitionToResponse(record *model.ArticleTransition) *types.ArticleTransitionResponse {
+	resp := &types.ArticleTransitionResponse{
+		ID:         record.ID,
+		ArticleID:  record.ArticleID,
+		FromStatus: record.FromStatus,
+		ToStatus:   record.ToStatus,
+		ActorID:    record.ActorID,
+		Comment:    record.Comment,
+		CreatedAt:  record.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+
+	if record.Actor != nil {
+		resp.ActorName = record.Actor.Nickname
+		if resp.ActorName == "" {
+			resp.ActorName = record.Actor.Username
+		}
+	}
+
+	return resp
+}
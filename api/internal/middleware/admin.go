@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+
+	"acupofcoffee/common/errorx"
+	"acupofcoffee/common/response"
+	"acupofcoffee/model"
+
+	"gorm.io/gorm"
+)
+
+// AdminMiddleware 管理员权限校验，需放在 AuthMiddleware 之后
+type AdminMiddleware struct {
+	DB *gorm.DB
+}
+
+func NewAdminMiddleware(db *gorm.DB) *AdminMiddleware {
+	return &AdminMiddleware{
+		DB: db,
+	}
+}
+
+func (m *AdminMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := r.Context().Value("userId").(uint)
+		if !ok {
+			response.Error(w, errorx.NewCodeError(http.StatusUnauthorized, "未登录"))
+			return
+		}
+
+		var user model.User
+		if err := m.DB.Select("id", "role", "status").First(&user, userID).Error; err != nil {
+			response.Error(w, errorx.NewCodeError(http.StatusUnauthorized, "用户不存在"))
+			return
+		}
+
+		if !user.IsActive() || !user.IsAdmin() {
+			response.Forbidden(w, "需要管理员权限")
+			return
+		}
+
+		next(w, r)
+	}
+}
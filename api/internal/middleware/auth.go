@@ -58,3 +58,28 @@ func (m *AuthMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
 		next(w, r.WithContext(ctx))
 	}
 }
+
+// OptionalHandle 用于公开接口：携带有效令牌时识别当前用户，否则按匿名访问处理
+func (m *AuthMiddleware) OptionalHandle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.SplitN(r.Header.Get("Authorization"), " ", 2)
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			next(w, r)
+			return
+		}
+
+		claims := jwt.MapClaims{}
+		token, err := jwt.ParseWithClaims(parts[1], claims, func(token *jwt.Token) (interface{}, error) {
+			return []byte(m.AccessSecret), nil
+		})
+		if err != nil || !token.Valid {
+			next(w, r)
+			return
+		}
+
+		if userID, ok := claims["userId"].(float64); ok {
+			r = r.WithContext(context.WithValue(r.Context(), "userId", uint(userID)))
+		}
+		next(w, r)
+	}
+}
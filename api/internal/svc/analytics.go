@@ -0,0 +1,265 @@
+package svc
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"acupofcoffee/api/internal/config"
+	"acupofcoffee/common/utils"
+	"acupofcoffee/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Analytics 文章访问统计缓冲：浏览和阅读上报先按文章和日期在内存中汇总，
+// 定期批量写入按天汇总的统计表，日期按配置的统计时区划分
+type Analytics struct {
+	db    *gorm.DB
+	loc   *time.Location
+	flush time.Duration
+
+	mu   sync.Mutex
+	days map[statDay]*dayBuffer
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+type statDay struct {
+	articleID uint
+	day       string
+}
+
+type dayBuffer struct {
+	views    int64
+	visitors map[string]struct{}
+	beacons  map[string]*beacon // 每个访客取本周期内最深的阅读进度
+}
+
+type beacon struct {
+	domain string
+	depth  int
+}
+
+func NewAnalytics(db *gorm.DB, c config.AnalyticsConfig) *Analytics {
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		panic("invalid analytics timezone: " + err.Error())
+	}
+
+	return &Analytics{
+		db:    db,
+		loc:   loc,
+		flush: c.FlushInterval,
+		days:  make(map[statDay]*dayBuffer),
+		done:  make(chan struct{}),
+	}
+}
+
+// Location 统计时区
+func (a *Analytics) Location() *time.Location {
+	return a.loc
+}
+
+// Day 时间所在的统计日期
+func (a *Analytics) Day(t time.Time) string {
+	return utils.GetStartOfDay(t.In(a.loc)).Format(utils.DateFormat)
+}
+
+// RecordView 记录一次已去重的浏览
+func (a *Analytics) RecordView(articleID uint, visitor string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	buf := a.buffer(articleID, time.Now())
+	buf.views++
+	buf.visitors[hashVisitor(visitor)] = struct{}{}
+}
+
+// RecordBeacon 记录访客上报的来源域名和阅读深度（0-100），每个访客每天只计入一次
+func (a *Analytics) RecordBeacon(articleID uint, visitor, domain string, depth int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	buf := a.buffer(articleID, time.Now())
+	key := hashVisitor(visitor)
+	if b, ok := buf.beacons[key]; ok {
+		if depth > b.depth {
+			b.depth = depth
+		}
+		return
+	}
+	buf.beacons[key] = &beacon{domain: domain, depth: depth}
+}
+
+func (a *Analytics) buffer(articleID uint, t time.Time) *dayBuffer {
+	key := statDay{articleID: articleID, day: a.Day(t)}
+	buf, ok := a.days[key]
+	if !ok {
+		buf = &dayBuffer{
+			visitors: make(map[string]struct{}),
+			beacons:  make(map[string]*beacon),
+		}
+		a.days[key] = buf
+	}
+	return buf
+}
+
+// Start 启动定期写库
+func (a *Analytics) Start() {
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+
+		ticker := time.NewTicker(a.flush)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-a.done:
+				return
+			case <-ticker.C:
+				if err := a.Flush(); err != nil {
+					logx.Errorf("flush analytics error: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop 停止定期写库，并写入剩余的统计
+func (a *Analytics) Stop() {
+	close(a.done)
+	a.wg.Wait()
+
+	if err := a.Flush(); err != nil {
+		logx.Errorf("flush analytics error: %v", err)
+	}
+}
+
+// Flush 将缓冲的统计写入数据库，失败时放回缓冲区等待下次写入
+func (a *Analytics) Flush() error {
+	a.mu.Lock()
+	days := a.days
+	a.days = make(map[statDay]*dayBuffer)
+	a.mu.Unlock()
+
+	if len(days) == 0 {
+		return nil
+	}
+
+	err := a.db.Transaction(func(tx *gorm.DB) error {
+		for key, buf := range days {
+			if err := flushDay(tx, key, buf); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		a.mu.Lock()
+		for key, buf := range days {
+			a.merge(key, buf)
+		}
+		a.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+func (a *Analytics) merge(key statDay, src *dayBuffer) {
+	dst, ok := a.days[key]
+	if !ok {
+		a.days[key] = src
+		return
+	}
+
+	dst.views += src.views
+	for v := range src.visitors {
+		dst.visitors[v] = struct{}{}
+	}
+	for v, b := range src.beacons {
+		if old, ok := dst.beacons[v]; !ok || b.depth > old.depth {
+			dst.beacons[v] = b
+		}
+	}
+}
+
+// flushDay 写入一篇文章一天的统计：访客和阅读上报借助访客去重表判断是否为当天首次
+func flushDay(tx *gorm.DB, key statDay, buf *dayBuffer) error {
+	stat := model.ArticleDailyStat{ArticleID: key.articleID, Day: key.day, Views: buf.views}
+	referrers := make(map[string]int64)
+
+	for v := range buf.beacons {
+		buf.visitors[v] = struct{}{}
+	}
+	if len(buf.visitors) > 0 {
+		rows := make([]model.ArticleDailyVisitor, 0, len(buf.visitors))
+		for v := range buf.visitors {
+			rows = append(rows, model.ArticleDailyVisitor{ArticleID: key.articleID, Day: key.day, Visitor: v})
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows)
+		if result.Error != nil {
+			return result.Error
+		}
+		stat.Visitors = result.RowsAffected
+	}
+
+	for v, b := range buf.beacons {
+		result := tx.Model(&model.ArticleDailyVisitor{}).
+			Where("article_id = ? AND day = ? AND visitor = ? AND reported = ?", key.articleID, key.day, v, false).
+			Update("reported", true)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		stat.ReadCount++
+		stat.ReadDepthTotal += int64(b.depth)
+		referrers[b.domain]++
+	}
+
+	if stat.Views > 0 || stat.Visitors > 0 || stat.ReadCount > 0 {
+		if err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "article_id"}, {Name: "day"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"views":            gorm.Expr("views + ?", stat.Views),
+				"visitors":         gorm.Expr("visitors + ?", stat.Visitors),
+				"read_count":       gorm.Expr("read_count + ?", stat.ReadCount),
+				"read_depth_total": gorm.Expr("read_depth_total + ?", stat.ReadDepthTotal),
+				"updated_at":       time.Now(),
+			}),
+		}).Create(&stat).Error; err != nil {
+			return err
+		}
+	}
+
+	for domain, n := range referrers {
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "article_id"}, {Name: "day"}, {Name: "domain"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"visits": gorm.Expr("visits + ?", n)}),
+		}).Create(&model.ArticleDailyReferrer{
+			ArticleID: key.articleID,
+			Day:       key.day,
+			Domain:    domain,
+			Visits:    n,
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hashVisitor 访客标识只保存哈希，不落库原始 IP
+func hashVisitor(visitor string) string {
+	h := fnv.New64a()
+	h.Write([]byte(visitor))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
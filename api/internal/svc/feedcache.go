@@ -0,0 +1,65 @@
+package svc
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	"acupofcoffee/api/internal/config"
+
+	"github.com/zeromicro/go-zero/core/collection"
+)
+
+// FeedCache 关注动态缓存。动态在读取时按关注列表实时查询（fan-out-on-read），
+// 这里缓存每个用户的关注列表和最近查询过的分页结果：关注列表在关注变化时失效，
+// 分页结果的键包含关注列表的指纹，关注变化后自然不再命中，其余情况按 PageTTL 过期
+type FeedCache struct {
+	followees *collection.Cache
+	pages     *collection.Cache
+}
+
+func NewFeedCache(c config.FeedConfig) *FeedCache {
+	followees, err := collection.NewCache(c.FolloweeTTL, collection.WithLimit(c.CacheLimit), collection.WithName("feed-followees"))
+	if err != nil {
+		panic("failed to create feed cache: " + err.Error())
+	}
+	pages, err := collection.NewCache(c.PageTTL, collection.WithLimit(c.CacheLimit), collection.WithName("feed-pages"))
+	if err != nil {
+		panic("failed to create feed cache: " + err.Error())
+	}
+
+	return &FeedCache{followees: followees, pages: pages}
+}
+
+// Followees 用户关注的作者ID，未命中时由 load 查询
+func (f *FeedCache) Followees(userID uint, load func() ([]uint, error)) ([]uint, error) {
+	v, err := f.followees.Take(followeesKey(userID), func() (any, error) {
+		return load()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]uint), nil
+}
+
+// InvalidateFollowees 关注或取消关注后调用
+func (f *FeedCache) InvalidateFollowees(userID uint) {
+	f.followees.Del(followeesKey(userID))
+}
+
+// Page 缓存一页动态，key 由调用方根据用户、游标和关注列表生成
+func (f *FeedCache) Page(key string, load func() (any, error)) (any, error) {
+	return f.pages.Take(key, load)
+}
+
+// Fingerprint 关注列表的指纹，用于区分关注变化前后的分页缓存
+func Fingerprint(ids []uint) string {
+	h := fnv.New64a()
+	for _, id := range ids {
+		fmt.Fprintf(h, "%d,", id)
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+func followeesKey(userID uint) string {
+	return fmt.Sprintf("followees:%d", userID)
+}
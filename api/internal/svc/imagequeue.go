@@ -0,0 +1,92 @@
+package svc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"acupofcoffee/api/internal/config"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// ImageQueue 图片处理队列：上传后把文件ID放入队列，由固定数量的 worker 在后台生成各尺寸的变体。
+// 队列只在内存中，满了或实例重启时丢失的任务由定时任务按数据库中的状态重新排队
+type ImageQueue struct {
+	workers int
+	timeout time.Duration
+
+	ids  chan uint
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewImageQueue(c config.ImageConfig) *ImageQueue {
+	workers := c.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	return &ImageQueue{
+		workers: workers,
+		timeout: c.Timeout,
+		ids:     make(chan uint, c.QueueSize),
+		done:    make(chan struct{}),
+	}
+}
+
+// Enqueue 加入队列，队列已满或已停止时返回 false
+func (q *ImageQueue) Enqueue(id uint) bool {
+	select {
+	case <-q.done:
+		return false
+	default:
+	}
+
+	select {
+	case q.ids <- id:
+		return true
+	default:
+		return false
+	}
+}
+
+// Start 启动 worker，process 处理一个文件
+func (q *ImageQueue) Start(process func(ctx context.Context, id uint) error) {
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+		go func() {
+			defer q.wg.Done()
+
+			for {
+				select {
+				case <-q.done:
+					return
+				case id := <-q.ids:
+					q.run(process, id)
+				}
+			}
+		}()
+	}
+}
+
+// Stop 停止 worker，等待正在处理的文件完成，队列中剩余的留给定时任务
+func (q *ImageQueue) Stop() {
+	close(q.done)
+	q.wg.Wait()
+}
+
+func (q *ImageQueue) run(process func(ctx context.Context, id uint) error, id uint) {
+	ctx, cancel := context.WithTimeout(context.Background(), q.timeout)
+	defer cancel()
+
+	defer func() {
+		if p := recover(); p != nil {
+			logx.Errorf("process image %d panic: %v", id, p)
+		}
+	}()
+
+	if err := process(ctx, id); err != nil {
+		logx.Errorf("process image %d error: %v", id, err)
+	}
+}
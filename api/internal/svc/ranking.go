@@ -0,0 +1,243 @@
+package svc
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"acupofcoffee/api/internal/config"
+	"acupofcoffee/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/redis"
+	"gorm.io/gorm"
+)
+
+const (
+	// 每隔多少个半衰期换一个新的有序集合，避免分值无限增大
+	trendingGeneration = 32
+	trendingKeyPrefix  = "acupofcoffee:{trending}:"
+)
+
+// trendingIncrScript 进入新的周期时先把上一周期的分值按比例折算过来，再累加本次分值；
+// 扣减后不再为正的文章直接移除，已被裁剪掉的文章不会因扣减产生负分
+const trendingIncrScript = `
+if redis.call('EXISTS', KEYS[1]) == 0 and redis.call('EXISTS', KEYS[2]) == 1 then
+	redis.call('ZUNIONSTORE', KEYS[1], 1, KEYS[2], 'WEIGHTS', ARGV[3])
+	redis.call('EXPIRE', KEYS[2], 3600)
+end
+local score = redis.call('ZINCRBY', KEYS[1], ARGV[1], ARGV[2])
+if tonumber(score) <= 0 then
+	redis.call('ZREM', KEYS[1], ARGV[2])
+	return '0'
+end
+return score
+`
+
+// Ranking 文章热度排行。热度为浏览、点赞、评论按时间指数衰减后的加权和：
+// 启用 Redis 时在有序集合中增量维护，未启用或 Redis 不可用时由数据库中的
+// 访问统计、点赞和评论记录计算，并缓存一段时间
+type Ranking struct {
+	db  *gorm.DB
+	rds *redis.Redis
+	cfg config.RankingConfig
+
+	mu       sync.Mutex
+	cached   []uint
+	cachedAt time.Time
+}
+
+func NewRanking(db *gorm.DB, c config.RankingConfig, rc redis.RedisConf) *Ranking {
+	r := &Ranking{db: db, cfg: c}
+	if c.Redis {
+		r.rds = redis.MustNewRedis(rc)
+	}
+	return r
+}
+
+// View 记录一次浏览
+func (r *Ranking) View(articleID uint) {
+	r.incr(articleID, r.cfg.ViewWeight, time.Now())
+}
+
+// Like 记录点赞
+func (r *Ranking) Like(articleID uint) {
+	r.incr(articleID, r.cfg.LikeWeight, time.Now())
+}
+
+// Unlike 取消点赞，按点赞时的权重扣回，反复点赞、取消不会改变热度
+func (r *Ranking) Unlike(articleID uint, likedAt time.Time) {
+	r.incr(articleID, -r.cfg.LikeWeight, likedAt)
+}
+
+// Comment 记录一条公开的评论
+func (r *Ranking) Comment(articleID uint) {
+	r.incr(articleID, r.cfg.CommentWeight, time.Now())
+}
+
+// incr 分值以当前周期的起点为基准按事件发生时间放大，越新的事件权重越大，排序等价于按当前时间衰减
+func (r *Ranking) incr(articleID uint, weight float64, at time.Time) {
+	if r.rds == nil || weight == 0 {
+		return
+	}
+
+	gen, _ := r.generation(time.Now())
+	exponent := r.halfLives(at) - float64(gen*trendingGeneration)
+	_, err := r.rds.Eval(trendingIncrScript,
+		[]string{trendingKey(gen), trendingKey(gen - 1)},
+		strconv.FormatFloat(weight*math.Exp2(exponent), 'g', -1, 64),
+		strconv.FormatUint(uint64(articleID), 10),
+		strconv.FormatFloat(math.Exp2(-trendingGeneration), 'g', -1, 64),
+	)
+	if err != nil {
+		logx.Errorf("incr trending score error: %v", err)
+	}
+}
+
+// Trending 按热度排序的文章ID，最多保留 Size 篇
+func (r *Ranking) Trending() ([]uint, error) {
+	if r.rds != nil {
+		ids, err := r.trendingFromRedis()
+		if err == nil {
+			return ids, nil
+		}
+		logx.Errorf("read trending from redis error: %v", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cached != nil && time.Since(r.cachedAt) < r.cfg.CacheTTL {
+		return r.cached, nil
+	}
+	ids, err := r.trendingFromDB()
+	if err != nil {
+		return nil, err
+	}
+	r.cached, r.cachedAt = ids, time.Now()
+	return ids, nil
+}
+
+// Trim 只保留排名靠前的文章，由后台任务周期调用
+func (r *Ranking) Trim() error {
+	if r.rds == nil {
+		return nil
+	}
+
+	gen, _ := r.generation(time.Now())
+	_, err := r.rds.Zremrangebyrank(trendingKey(gen), 0, int64(-r.cfg.Size-1))
+	return err
+}
+
+func (r *Ranking) trendingFromRedis() ([]uint, error) {
+	gen, _ := r.generation(time.Now())
+
+	// 新周期还没有写入时，上一周期的排序仍然有效
+	members, err := r.rds.Zrevrange(trendingKey(gen), 0, int64(r.cfg.Size-1))
+	if err == nil && len(members) == 0 {
+		members, err = r.rds.Zrevrange(trendingKey(gen-1), 0, int64(r.cfg.Size-1))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(members))
+	for _, m := range members {
+		if id, err := strconv.ParseUint(m, 10, 64); err == nil {
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids, nil
+}
+
+// createdDay 按创建日期分组的表达式。MySQL 开启 parseTime 时 DATE() 会被解析成时间，
+// 转成字符串后两种数据库都返回 2006-01-02
+const createdDay = "CAST(DATE(created_at) AS CHAR)"
+
+// trendingFromDB 按天汇总统计窗口内的浏览、点赞和评论，以每天的中午计算衰减
+func (r *Ranking) trendingFromDB() ([]uint, error) {
+	now := time.Now()
+	since := now.AddDate(0, 0, -r.cfg.Window)
+
+	var rows []struct {
+		ArticleID uint
+		Day       string
+		Total     int64
+	}
+	scores := make(map[uint]float64)
+	add := func(weight float64) {
+		for _, row := range rows {
+			day, err := time.ParseInLocation("2006-01-02", row.Day, time.UTC)
+			if err != nil {
+				logx.Errorf("parse trending day %q of article %d error: %v", row.Day, row.ArticleID, err)
+				continue
+			}
+			age := now.Sub(day.Add(12 * time.Hour))
+			if age < 0 {
+				age = 0
+			}
+			scores[row.ArticleID] += weight * float64(row.Total) * math.Exp2(-age.Hours()/r.cfg.HalfLife.Hours())
+		}
+	}
+
+	// 统计表的日期按统计时区划分，这里只用于粗略衰减，误差不超过一天
+	if err := r.db.Model(&model.ArticleDailyStat{}).
+		Select("article_id, day, views AS total").
+		Where("day >= ?", since.Format("2006-01-02")).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	add(r.cfg.ViewWeight)
+
+	rows = nil
+	if err := r.db.Model(&model.ArticleLike{}).
+		Select("article_id, "+createdDay+" AS day, COUNT(*) AS total").
+		Where("created_at >= ?", since).
+		Group("article_id, " + createdDay).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	add(r.cfg.LikeWeight)
+
+	rows = nil
+	if err := r.db.Model(&model.Comment{}).
+		Select("article_id, "+createdDay+" AS day, COUNT(*) AS total").
+		Where("created_at >= ? AND status = ?", since, model.CommentStatusApproved).
+		Group("article_id, " + createdDay).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	add(r.cfg.CommentWeight)
+
+	ids := make([]uint, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if scores[ids[i]] != scores[ids[j]] {
+			return scores[ids[i]] > scores[ids[j]]
+		}
+		return ids[i] > ids[j]
+	})
+	if len(ids) > r.cfg.Size {
+		ids = ids[:r.cfg.Size]
+	}
+	return ids, nil
+}
+
+// generation 当前所在周期及相对周期起点经过的半衰期数
+func (r *Ranking) generation(t time.Time) (int64, float64) {
+	halfLives := r.halfLives(t)
+	gen := int64(halfLives / trendingGeneration)
+	return gen, halfLives - float64(gen*trendingGeneration)
+}
+
+func (r *Ranking) halfLives(t time.Time) float64 {
+	return float64(t.Unix()) / r.cfg.HalfLife.Seconds()
+}
+
+func trendingKey(gen int64) string {
+	return trendingKeyPrefix + strconv.FormatInt(gen, 10)
+}
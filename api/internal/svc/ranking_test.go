@@ -0,0 +1,94 @@
+package svc
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"acupofcoffee/api/internal/config"
+	"acupofcoffee/model"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/zeromicro/go-zero/core/stores/redis"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestTrendingFromDBCountsLikesAndComments(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.ArticleDailyStat{}, &model.ArticleLike{}, &model.Comment{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	// 文章 1 只有浏览，文章 2 只有点赞，文章 3 只有评论
+	now := time.Now()
+	db.Create(&model.ArticleDailyStat{ArticleID: 1, Day: now.Format("2006-01-02"), Views: 1})
+	db.Create(&model.ArticleLike{ArticleID: 2, UserID: 1, CreatedAt: now})
+	db.Create(&model.Comment{ArticleID: 3, UserID: 1, Body: "hi", Status: model.CommentStatusApproved})
+
+	r := NewRanking(db, config.RankingConfig{
+		HalfLife:      24 * time.Hour,
+		Window:        7,
+		ViewWeight:    1,
+		LikeWeight:    5,
+		CommentWeight: 10,
+		Size:          10,
+	}, config.Config{}.Redis)
+
+	ids, err := r.trendingFromDB()
+	if err != nil {
+		t.Fatalf("trending: %v", err)
+	}
+	want := []uint{3, 2, 1}
+	if len(ids) != len(want) {
+		t.Fatalf("got %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("got %v, want %v", ids, want)
+		}
+	}
+}
+
+func TestRedisUnlikeRevertsLike(t *testing.T) {
+	mr := miniredis.RunT(t)
+	r := NewRanking(nil, config.RankingConfig{
+		Redis:      true,
+		HalfLife:   24 * time.Hour,
+		ViewWeight: 1,
+		LikeWeight: 5,
+		Size:       10,
+	}, redis.RedisConf{Host: mr.Addr(), Type: redis.NodeType})
+
+	score := func(id string) float64 {
+		gen, _ := r.generation(time.Now())
+		if !mr.Exists(trendingKey(gen)) {
+			return 0
+		}
+		s, _ := mr.ZScore(trendingKey(gen), id)
+		return s
+	}
+
+	r.View(1)
+	before := score("1")
+
+	// 点赞一天后取消，只扣回点赞时的分值，不会低于点赞前
+	likedAt := time.Now().Add(-24 * time.Hour)
+	r.incr(1, 5, likedAt)
+	r.Unlike(1, likedAt)
+	if got := score("1"); math.Abs(got-before) > before*1e-9 {
+		t.Fatalf("score after like and unlike = %v, want %v", got, before)
+	}
+
+	// 已被裁剪掉的文章取消点赞不会留下负分
+	r.Unlike(2, time.Now())
+	if gen, _ := r.generation(time.Now()); mr.Exists(trendingKey(gen)) {
+		if members, _ := mr.ZMembers(trendingKey(gen)); len(members) != 1 {
+			t.Fatalf("members = %v, want only article 1", members)
+		}
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"strings"
 
 	"acupofcoffee/api/internal/config"
+	"acupofcoffee/common/richtext"
+	"acupofcoffee/common/storage"
 	"acupofcoffee/model"
 
+	"github.com/zeromicro/go-zero/core/logx"
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -13,19 +16,85 @@ import (
 )
 
 type ServiceContext struct {
-	Config config.Config
-	DB     *gorm.DB
+	Config    config.Config
+	DB        *gorm.DB
+	Views     *ViewCounter
+	Analytics *Analytics
+	Ranking   *Ranking
+	Feed      *FeedCache
+	Storage   storage.Storage
+	Images    *ImageQueue
 }
 
 func NewServiceContext(c config.Config) *ServiceContext {
 	db := initDB(c.MySQL)
+	backfillSummaries(db, c.Summary)
 
 	return &ServiceContext{
-		Config: c,
-		DB:     db,
+		Config:    c,
+		DB:        db,
+		Views:     NewViewCounter(db, c.View),
+		Analytics: NewAnalytics(db, c.Analytics),
+		Ranking:   NewRanking(db, c.Ranking, c.Redis),
+		Feed:      NewFeedCache(c.Feed),
+		Storage:   newStorage(c.Media),
+		Images:    NewImageQueue(c.Media.Image),
 	}
 }
 
+// backfillSummaries 自动摘要上线前没有填写摘要的文章，按正文补生成。
+// 正文没有文字的文章生成空摘要，同样标记为已生成，下次启动只查到新增的未处理文章。
+// 补生成失败不影响启动，只记录日志，下次启动时重试
+func backfillSummaries(db *gorm.DB, c config.SummaryConfig) {
+	var articles []model.Article
+	err := db.Select("id", "content").
+		Where("summary = ? AND summary_generated = ?", "", false).
+		FindInBatches(&articles, 200, func(tx *gorm.DB, batch int) error {
+			for _, article := range articles {
+				// 不更新修改时间，避免订阅源和站点地图把这些文章当成刚修改过；
+				// 期间作者已经填写了摘要的文章不覆盖
+				if err := db.Model(&model.Article{}).
+					Where("id = ? AND summary = ?", article.ID, "").
+					UpdateColumns(map[string]interface{}{
+						"summary":           richtext.Summary(richtext.Parse(article.Content), c.Length, c.MinParagraph),
+						"summary_generated": true,
+					}).Error; err != nil {
+					logx.Errorf("backfill summary of article %d error: %v", article.ID, err)
+				}
+			}
+			return nil
+		}).Error
+	if err != nil {
+		logx.Errorf("backfill summaries error: %v", err)
+	}
+}
+
+// newStorage 按配置创建文件存储后端
+func newStorage(c config.MediaConfig) storage.Storage {
+	var s storage.Storage
+	var err error
+
+	switch c.Storage {
+	case "s3":
+		s, err = storage.NewS3(storage.S3Options{
+			Endpoint:  c.S3.Endpoint,
+			Region:    c.S3.Region,
+			Bucket:    c.S3.Bucket,
+			AccessKey: c.S3.AccessKey,
+			SecretKey: c.S3.SecretKey,
+			PathStyle: c.S3.PathStyle,
+			PublicURL: c.S3.PublicURL,
+		})
+	default:
+		s, err = storage.NewLocal(c.Local.Root, c.Local.BaseURL)
+	}
+	if err != nil {
+		panic("failed to init storage: " + err.Error())
+	}
+
+	return s
+}
+
 func initDB(cfg config.MySQLConfig) *gorm.DB {
 	var db *gorm.DB
 	var err error
@@ -61,9 +130,60 @@ func initDB(cfg config.MySQLConfig) *gorm.DB {
 		&model.Article{},
 		&model.ArticleVersion{},
 		&model.ArticleDraft{},
+		&model.DraftSnapshot{},
+		&model.ArticleSlug{},
+		&model.ArticleTransition{},
+		&model.Tag{},
+		&model.Category{},
+		&model.ArticleTag{},
+		&model.ArticleCategory{},
+		&model.Series{},
+		&model.SeriesArticle{},
+		&model.JobLease{},
+		&model.ArticleLike{},
+		&model.ArticleReaction{},
+		&model.Bookmark{},
+		&model.Comment{},
+		&model.Notification{},
+		&model.ArticleDailyStat{},
+		&model.ArticleDailyReferrer{},
+		&model.ArticleDailyVisitor{},
+		&model.Follow{},
+		&model.Media{},
+		&model.MediaReference{},
+		&model.MediaVariant{},
+		&model.ArticleCollaborator{},
 	); err != nil {
 		panic("failed to migrate database: " + err.Error())
 	}
 
+	// 草稿早期只在 article_id 上建了唯一索引，导致不同用户的新文章草稿互相冲突
+	if db.Migrator().HasIndex(&model.ArticleDraft{}, "idx_article_drafts_article_id") {
+		if err := db.Migrator().DropIndex(&model.ArticleDraft{}, "idx_article_drafts_article_id"); err != nil {
+			panic("failed to migrate database: " + err.Error())
+		}
+	}
+
+	// 首次发布时间字段新增前已发布的文章，按最早一次发布的流转记录补齐
+	if err := db.Exec(`UPDATE articles SET published_at = COALESCE((
+		SELECT MIN(article_transitions.created_at) FROM article_transitions
+		WHERE article_transitions.article_id = articles.id AND article_transitions.to_status = ?
+	), updated_at) WHERE status = ? AND published_at IS NULL`,
+		model.ArticleStatusPublished, model.ArticleStatusPublished).Error; err != nil {
+		panic("failed to migrate database: " + err.Error())
+	}
+
+	// 记录编辑者之前的文章，当前内容视为作者编辑
+	if err := db.Exec("UPDATE articles SET editor_id = author_id WHERE editor_id = 0").Error; err != nil {
+		panic("failed to migrate database: " + err.Error())
+	}
+
+	// 图片处理上线前上传的图片，交给定时任务补生成变体
+	if err := db.Model(&model.Media{}).
+		Where("variant_status = ? AND width > 0 AND content_type LIKE ?", "", "image/%").
+		Update("variant_status", model.MediaVariantPending).Error; err != nil {
+		panic("failed to migrate database: " + err.Error())
+	}
+
 	return db
 }
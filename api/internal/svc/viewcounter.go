@@ -0,0 +1,142 @@
+package svc
+
+import (
+	"sync"
+	"time"
+
+	"acupofcoffee/api/internal/config"
+	"acupofcoffee/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+// ViewCounter 浏览量缓冲：同一访客在去重窗口内重复浏览同一文章只计一次，
+// 计数先累积在内存中，由每个实例定期批量写入数据库，读接口不再写库
+type ViewCounter struct {
+	db     *gorm.DB
+	window time.Duration
+	flush  time.Duration
+
+	mu      sync.Mutex
+	seen    map[viewKey]time.Time // 访客浏览记录的过期时间
+	pending map[uint]int64        // 尚未写入数据库的浏览量
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+type viewKey struct {
+	articleID uint
+	visitor   string
+}
+
+func NewViewCounter(db *gorm.DB, c config.ViewConfig) *ViewCounter {
+	return &ViewCounter{
+		db:      db,
+		window:  c.DedupWindow,
+		flush:   c.FlushInterval,
+		seen:    make(map[viewKey]time.Time),
+		pending: make(map[uint]int64),
+		done:    make(chan struct{}),
+	}
+}
+
+// Record 记录一次浏览，去重窗口内的重复浏览返回 false
+func (c *ViewCounter) Record(articleID uint, visitor string) bool {
+	key := viewKey{articleID: articleID, visitor: visitor}
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if expireAt, ok := c.seen[key]; ok && now.Before(expireAt) {
+		return false
+	}
+	c.seen[key] = now.Add(c.window)
+	c.pending[articleID]++
+	return true
+}
+
+// Pending 尚未写入数据库的浏览量，用于返回最新的计数
+func (c *ViewCounter) Pending(articleID uint) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.pending[articleID]
+}
+
+// Start 启动定期写库
+func (c *ViewCounter) Start() {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+
+		ticker := time.NewTicker(c.flush)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-c.done:
+				return
+			case <-ticker.C:
+				if err := c.Flush(); err != nil {
+					logx.Errorf("flush view counts error: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop 停止定期写库，并写入剩余的计数
+func (c *ViewCounter) Stop() {
+	close(c.done)
+	c.wg.Wait()
+
+	if err := c.Flush(); err != nil {
+		logx.Errorf("flush view counts error: %v", err)
+	}
+}
+
+// Flush 将累积的浏览量写入数据库，增量相同的文章合并为一条语句；写入失败时计数放回缓冲区
+func (c *ViewCounter) Flush() error {
+	now := time.Now()
+
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = make(map[uint]int64)
+	for key, expireAt := range c.seen {
+		if !now.Before(expireAt) {
+			delete(c.seen, key)
+		}
+	}
+	c.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	batches := make(map[int64][]uint)
+	for id, n := range pending {
+		batches[n] = append(batches[n], id)
+	}
+
+	err := c.db.Transaction(func(tx *gorm.DB) error {
+		for n, ids := range batches {
+			if err := tx.Model(&model.Article{}).Where("id IN ?", ids).
+				UpdateColumn("view_count", gorm.Expr("view_count + ?", n)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.mu.Lock()
+		for id, n := range pending {
+			c.pending[id] += n
+		}
+		c.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package types
+
+// ============== 访问统计 ==============
+
+type ArticleBeaconRequest struct {
+	ID       uint   `json:"-" path:"id"`
+	Referrer string `json:"referrer,optional"` // 页面的 document.referrer
+	Depth    int    `json:"depth,optional"`    // 最大阅读进度，0-100
+}
+
+type ArticleStatsRequest struct {
+	ID   uint   `json:"-" path:"id"`
+	From string `json:"from,optional" form:"from,optional"` // 2006-01-02，默认 To 之前 29 天
+	To   string `json:"to,optional" form:"to,optional"`     // 2006-01-02，默认今天
+}
+
+type ArticleStatsPoint struct {
+	Date         string  `json:"date,omitempty"`
+	Views        int64   `json:"views"`
+	Visitors     int64   `json:"visitors"` // 合计时为每天独立访客数之和
+	Reads        int64   `json:"reads"`
+	AvgReadDepth float64 `json:"avgReadDepth"` // 百分比
+}
+
+type ReferrerStat struct {
+	Domain string `json:"domain"` // 空表示直接访问
+	Visits int64  `json:"visits"`
+}
+
+type ArticleStatsResponse struct {
+	ArticleID uint                 `json:"articleId"`
+	Timezone  string               `json:"timezone"`
+	From      string               `json:"from"`
+	To        string               `json:"to"`
+	Total     ArticleStatsPoint    `json:"total"`
+	Series    []*ArticleStatsPoint `json:"series"`
+	Referrers []*ReferrerStat      `json:"referrers"`
+}
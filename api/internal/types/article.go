@@ -6,23 +6,37 @@ type CreateArticleRequest struct {
 	Title   string `json:"title"`
 	Content string `json:"content"` // JSON 字符串格式的富文本内容
 	Cover   string `json:"cover,optional"`
-	Summary string `json:"summary,optional"`
-	Status  int8   `json:"status,optional"` // 0:草稿 1:发布
+	Summary string `json:"summary,optional"` // 不填时从正文自动生成
+	Status  int8   `json:"status,optional"`  // 0:草稿 1:发布
+	Slug    string `json:"slug,optional"`    // 永久链接，不传则根据标题生成
+
+	PublishAt   string `json:"publishAt,optional"`   // 定时发布，格式 2006-01-02 15:04:05
+	UnpublishAt string `json:"unpublishAt,optional"` // 定时下线
+
+	Tags        []string `json:"tags,optional"`        // 标签名，不存在时自动创建
+	CategoryIDs []uint   `json:"categoryIds,optional"` // 分类ID
+
+	DraftID uint `json:"draftId,optional"` // 从新文章草稿创建时传入，创建成功后删除该草稿
 }
 
 type UpdateArticleRequest struct {
-	ID      uint   `json:"id" path:"id"`
+	ID      uint   `json:"-" path:"id"`
 	Title   string `json:"title,optional"`
 	Content string `json:"content,optional"` // JSON 字符串格式
 	Cover   string `json:"cover,optional"`
-	Summary string `json:"summary,optional"`
+	Summary string `json:"summary,optional"` // 不传表示不修改；自动生成的摘要随正文更新，传入后不再自动生成
 	Status  int8   `json:"status,optional"`
 	Remark  string `json:"remark,optional"` // 版本备注
+	Slug    string `json:"slug,optional"`   // 不传时，标题变化会重新生成
+
+	Tags        []string `json:"tags,optional"`        // 不传表示不修改，传空数组表示清空
+	CategoryIDs []uint   `json:"categoryIds,optional"` // 同上
 }
 
 type ArticleResponse struct {
 	ID         uint   `json:"id"`
 	Title      string `json:"title"`
+	Slug       string `json:"slug"`
 	Content    string `json:"content"` // JSON 字符串
 	Cover      string `json:"cover"`
 	Summary    string `json:"summary"`
@@ -34,6 +48,28 @@ type ArticleResponse struct {
 	LikeCount  int64  `json:"likeCount"`
 	CreatedAt  string `json:"createdAt"`
 	UpdatedAt  string `json:"updatedAt"`
+
+	CoverSrcset      string `json:"coverSrcset,omitempty"`  // 封面为上传的图片且已生成缩放版本时返回
+	SummaryGenerated bool   `json:"summaryGenerated"`       // 摘要是否由正文自动生成
+	RestoredFrom     int    `json:"restoredFrom,omitempty"` // 当前内容恢复自的版本号
+
+	Tags       []*TagResponse      `json:"tags"`
+	Categories []*CategoryResponse `json:"categories"`
+	CoAuthors  []*ArticleCoAuthor  `json:"coAuthors"` // 已接受邀请的共同作者，不含作者本人
+	Series     *ArticleSeriesNav   `json:"series,omitempty"`
+
+	LikedByMe      bool               `json:"likedByMe"`
+	BookmarkedByMe bool               `json:"bookmarkedByMe"`
+	Reactions      []*ReactionSummary `json:"reactions,omitempty"` // 仅详情返回
+	SEO            *ArticleSEO        `json:"seo,omitempty"`       // 仅详情返回
+
+	PublishAt   string `json:"publishAt,omitempty"`
+	UnpublishAt string `json:"unpublishAt,omitempty"`
+	PublishedAt string `json:"publishedAt,omitempty"` // 首次发布时间
+}
+
+type ArticleSlugRequest struct {
+	Slug string `json:"-" path:"slug"`
 }
 
 type ArticleListRequest struct {
@@ -42,25 +78,166 @@ type ArticleListRequest struct {
 	Status   *int8  `json:"status" form:"status,optional"`
 	Keyword  string `json:"keyword" form:"keyword,optional"`
 	AuthorID uint   `json:"authorId" form:"authorId,optional"`
+
+	TagID      uint   `json:"tagId" form:"tagId,optional"`
+	Tag        string `json:"tag" form:"tag,optional"` // 按标签名筛选
+	CategoryID uint   `json:"categoryId" form:"categoryId,optional"`
+
+	Statuses      []int8 `json:"statuses" form:"statuses,optional"`           // 多个状态，status 传入多个值（逗号分隔或重复参数）时使用
+	CreatedFrom   string `json:"createdFrom" form:"createdFrom,optional"`     // 创建时间范围，格式 2006-01-02 或 2006-01-02 15:04:05
+	CreatedTo     string `json:"createdTo" form:"createdTo,optional"`         // 只有日期时包含当天
+	PublishedFrom string `json:"publishedFrom" form:"publishedFrom,optional"` // 首次发布时间范围，格式同上
+	PublishedTo   string `json:"publishedTo" form:"publishedTo,optional"`
+	HasCover      *bool  `json:"hasCover" form:"hasCover,optional"`
+
+	Sort string `json:"sort" form:"sort,optional"` // latest（默认）/oldest/updated/trending/views/likes
+	Days int    `json:"days" form:"days,optional"` // sort=views 时的统计天数
+
+	Cursor    string `json:"cursor" form:"cursor,optional"` // 上一页返回的 nextCursor，传入后忽略 page
+	WithTotal *bool  `json:"total" form:"total,optional"`   // 是否统计总数，默认按页码分页时统计、按游标分页时不统计
 }
 
+// ArticleListResponse 未统计总数时 total 为 -1
 type ArticleListResponse struct {
 	PageResponse
+	NextCursor string `json:"nextCursor,omitempty"` // 还有下一页时返回，与本次的排序和筛选条件一起使用
+	HasMore    bool   `json:"hasMore"`
+}
+
+// ============== 批量操作 ==============
+
+type ArticleBatchRequest struct {
+	Action string              `json:"action"`          // publish/archive/delete/restore/change-author/add-tag
+	IDs    []uint              `json:"ids,optional"`    // 与 filter 二选一
+	Filter *ArticleBatchFilter `json:"filter,optional"` // 非管理员只匹配自己的文章；restore 匹配回收站中的文章
+
+	AuthorID uint     `json:"authorId,optional"` // change-author 的新作者
+	Tags     []string `json:"tags,optional"`     // add-tag 添加的标签名，不存在时自动创建
+
+	Atomic bool `json:"atomic,optional"` // 任一篇失败时全部不执行，默认逐篇执行
+	DryRun bool `json:"dryRun,optional"` // 只检查权限和可执行性，不做修改
+}
+
+type ArticleBatchFilter struct {
+	Status     *int8  `json:"status,optional"`
+	AuthorID   uint   `json:"authorId,optional"`
+	Keyword    string `json:"keyword,optional"`
+	TagID      uint   `json:"tagId,optional"`
+	Tag        string `json:"tag,optional"`
+	CategoryID uint   `json:"categoryId,optional"`
+}
+
+type ArticleBatchResponse struct {
+	Action    string                `json:"action"`
+	DryRun    bool                  `json:"dryRun"`
+	Atomic    bool                  `json:"atomic"`
+	Total     int                   `json:"total"`
+	Succeeded int                   `json:"succeeded"` // dryRun 时为可以执行的篇数
+	Skipped   int                   `json:"skipped"`
+	Failed    int                   `json:"failed"`
+	Results   []*ArticleBatchResult `json:"results"`
+}
+
+type ArticleBatchResult struct {
+	ID      uint   `json:"id"`
+	Title   string `json:"title,omitempty"`
+	Result  string `json:"result"`         // ok/skipped/failed/aborted，aborted 表示因其他文章失败而未执行（atomic）
+	Code    int    `json:"code,omitempty"` // failed 时的错误码，与接口错误码一致
+	Message string `json:"message,omitempty"`
+}
+
+// ============== 定时发布 ==============
+
+type ArticleScheduleRequest struct {
+	ID          uint   `json:"-" path:"id"`
+	PublishAt   string `json:"publishAt,optional"`   // 格式 2006-01-02 15:04:05，空表示取消
+	UnpublishAt string `json:"unpublishAt,optional"` // 同上
+}
+
+type ArticleScheduleResponse struct {
+	ArticleID   uint   `json:"articleId"`
+	PublishAt   string `json:"publishAt"`
+	UnpublishAt string `json:"unpublishAt"`
+}
+
+type ArticleScheduleItem struct {
+	ArticleID uint   `json:"articleId"`
+	Title     string `json:"title"`
+	Status    int8   `json:"status"`
+	Action    string `json:"action"` // publish/unpublish
+	At        string `json:"at"`
+}
+
+// ============== 审核流程 ==============
+
+type ArticleReviewRequest struct {
+	ID      uint   `json:"-" path:"id"`
+	Comment string `json:"comment,optional"` // 审核意见，驳回时必填
+}
+
+type ArticleTransitionResponse struct {
+	ID         uint   `json:"id,omitempty"`
+	ArticleID  uint   `json:"articleId"`
+	FromStatus int8   `json:"fromStatus"`
+	ToStatus   int8   `json:"toStatus"`
+	ActorID    uint   `json:"actorId"`
+	ActorName  string `json:"actorName,omitempty"`
+	Comment    string `json:"comment"`
+	CreatedAt  string `json:"createdAt,omitempty"`
+}
+
+type ReviewQueueItem struct {
+	ArticleID   uint   `json:"articleId"`
+	Title       string `json:"title"`
+	Summary     string `json:"summary"`
+	AuthorID    uint   `json:"authorId"`
+	AuthorName  string `json:"authorName"`
+	Version     int    `json:"version"`
+	SubmittedAt string `json:"submittedAt"`
+	Comment     string `json:"comment"` // 提交时的说明
 }
 
 // ============== 实时保存草稿 ==============
 
 type SaveDraftRequest struct {
+	DraftID   uint   `json:"draftId,optional"`   // 继续编辑已有的新文章草稿
 	ArticleID uint   `json:"articleId,optional"` // 0 表示新文章
+	Name      string `json:"name,optional"`      // 新文章草稿名称，不传时自动生成
 	Title     string `json:"title,optional"`
 	Content   string `json:"content,optional"`
 }
 
 type SaveDraftResponse struct {
 	DraftID uint   `json:"draftId"`
+	Name    string `json:"name,omitempty"`
 	SavedAt string `json:"savedAt"`
 }
 
+type DraftResponse struct {
+	ID           uint   `json:"id"`
+	ArticleID    uint   `json:"articleId"`              // 0 表示新文章
+	ArticleTitle string `json:"articleTitle,omitempty"` // 已有文章的当前标题
+	Name         string `json:"name,omitempty"`
+	Title        string `json:"title"`
+	Content      string `json:"content,omitempty"` // 列表中不返回
+	UpdatedAt    string `json:"updatedAt"`
+}
+
+type DraftSnapshotRequest struct {
+	ID         uint `json:"-" path:"id"`
+	SnapshotID uint `json:"-" path:"snapshotId"`
+}
+
+type DraftSnapshotResponse struct {
+	ID        uint   `json:"id"`
+	DraftID   uint   `json:"draftId"`
+	Title     string `json:"title"`
+	Content   string `json:"content,omitempty"` // 列表中不返回
+	Size      int    `json:"size"`              // 内容字符数
+	Pinned    bool   `json:"pinned"`            // 内容骤减前自动保留的快照
+	CreatedAt string `json:"createdAt"`
+}
+
 // ============== 版本历史 ==============
 
 type ArticleVersionListRequest struct {
@@ -68,15 +245,69 @@ type ArticleVersionListRequest struct {
 }
 
 type ArticleVersionResponse struct {
-	ID        uint   `json:"id"`
-	ArticleID uint   `json:"articleId"`
-	Title     string `json:"title"`
-	Content   string `json:"content"`
+	ID               uint   `json:"id"`
+	ArticleID        uint   `json:"articleId"`
+	Title            string `json:"title"`
+	Content          string `json:"content"`
+	Version          int    `json:"version"`
+	Remark           string `json:"remark"`
+	Cover            string `json:"cover"`
+	Summary          string `json:"summary"`
+	SummaryGenerated bool   `json:"summaryGenerated"`
+	Status           *int8  `json:"status"`                 // 早期版本为空
+	RestoredFrom     int    `json:"restoredFrom,omitempty"` // 该版本内容恢复自的版本号
+	EditorID         uint   `json:"editorId"`               // 产生该版本内容的用户，早期版本为 0
+	EditorName       string `json:"editorName,omitempty"`
+	CreatedAt        string `json:"createdAt"`
+}
+
+type CompareVersionsRequest struct {
+	ID   uint   `json:"-" path:"id"`
+	From string `json:"-" path:"a"` // 版本号或 current
+	To   string `json:"-" path:"b"`
+}
+
+type VersionRef struct {
 	Version   int    `json:"version"`
-	Remark    string `json:"remark"`
+	Current   bool   `json:"current"`
+	Title     string `json:"title"`
+	Remark    string `json:"remark,omitempty"`
 	CreatedAt string `json:"createdAt"`
 }
 
+type InlineDiff struct {
+	Op   string `json:"op"` // equal/insert/delete
+	Text string `json:"text"`
+}
+
+type BlockDiff struct {
+	Op      string        `json:"op"`   // equal/insert/delete/modify
+	Type    string        `json:"type"` // paragraph/heading/list-item/blockquote/code/image/rule
+	Level   int           `json:"level,omitempty"`
+	OldText string        `json:"oldText,omitempty"`
+	NewText string        `json:"newText,omitempty"`
+	Inline  []*InlineDiff `json:"inline,omitempty"`
+}
+
+type VersionDiffStats struct {
+	BlocksAdded    int `json:"blocksAdded"`
+	BlocksRemoved  int `json:"blocksRemoved"`
+	BlocksModified int `json:"blocksModified"`
+	CharsAdded     int `json:"charsAdded"`
+	CharsRemoved   int `json:"charsRemoved"`
+}
+
+type VersionDiffResponse struct {
+	ArticleID    uint             `json:"articleId"`
+	From         *VersionRef      `json:"from"`
+	To           *VersionRef      `json:"to"`
+	TitleChanged bool             `json:"titleChanged"`
+	Title        []*InlineDiff    `json:"title"`
+	Blocks       []*BlockDiff     `json:"blocks"`
+	Stats        VersionDiffStats `json:"stats"`
+	Unified      string           `json:"unified"`
+}
+
 // ============== WebSocket 实时同步 ==============
 
 type ArticleSyncMessage struct {
@@ -93,3 +324,110 @@ type CursorPosition struct {
 	Index  int `json:"index"`
 	Length int `json:"length"`
 }
+
+// ============== 订阅源 ==============
+
+type SyndicationRequest struct {
+	Author   string `form:"author,optional"` // 用户名
+	Tag      string `form:"tag,optional"`
+	Category string `form:"category,optional"`
+}
+
+// ============== SEO ==============
+
+// ArticleSEO 文章页的搜索引擎和社交分享元数据
+type ArticleSEO struct {
+	CanonicalURL string                 `json:"canonicalUrl"`
+	Title        string                 `json:"title"`
+	Description  string                 `json:"description"`
+	OpenGraph    *OpenGraphMeta         `json:"openGraph"`
+	Twitter      *TwitterCardMeta       `json:"twitter"`
+	JSONLD       map[string]interface{} `json:"jsonLd"` // schema.org Article 结构化数据
+}
+
+// OpenGraphMeta 对应 og:* 和 article:* 标签
+type OpenGraphMeta struct {
+	Type          string   `json:"type"`
+	Title         string   `json:"title"`
+	Description   string   `json:"description"`
+	URL           string   `json:"url"`
+	Image         string   `json:"image,omitempty"`
+	SiteName      string   `json:"siteName"`
+	Locale        string   `json:"locale"`
+	PublishedTime string   `json:"publishedTime,omitempty"`
+	ModifiedTime  string   `json:"modifiedTime"`
+	Author        string   `json:"author,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+// TwitterCardMeta 对应 twitter:* 标签
+type TwitterCardMeta struct {
+	Card        string `json:"card"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Image       string `json:"image,omitempty"`
+}
+
+// ============== 回收站 ==============
+
+type TrashItem struct {
+	ID        uint   `json:"id"`
+	Title     string `json:"title"`
+	Slug      string `json:"slug"`
+	Cover     string `json:"cover"`
+	Summary   string `json:"summary"`
+	Status    int8   `json:"status"`
+	DeletedAt string `json:"deletedAt"`
+	PurgeAt   string `json:"purgeAt"` // 到期后自动永久删除
+}
+
+// ============== 协作者 ==============
+
+// ArticleCoAuthor 文章署名的共同作者
+type ArticleCoAuthor struct {
+	UserID uint   `json:"userId"`
+	Name   string `json:"name"`
+	Avatar string `json:"avatar"`
+}
+
+type InviteCollaboratorRequest struct {
+	ID       uint   `json:"-" path:"id"`
+	Username string `json:"username,optional"` // 用户名和邮箱填写其一
+	Email    string `json:"email,optional"`
+	Role     string `json:"role"` // co-author/editor/viewer
+}
+
+type UpdateCollaboratorRequest struct {
+	ID     uint   `json:"-" path:"id"`
+	UserID uint   `json:"-" path:"userId"`
+	Role   string `json:"role"`
+}
+
+type CollaboratorRequest struct {
+	ID     uint `json:"-" path:"id"`
+	UserID uint `json:"-" path:"userId"`
+}
+
+type CollaboratorResponse struct {
+	ID         uint   `json:"id"` // 作者本人为 0
+	UserID     uint   `json:"userId"`
+	Username   string `json:"username"`
+	Nickname   string `json:"nickname"`
+	Avatar     string `json:"avatar"`
+	Role       string `json:"role"`    // owner/co-author/editor/viewer
+	Pending    bool   `json:"pending"` // 邀请尚未接受
+	InvitedBy  uint   `json:"invitedBy,omitempty"`
+	AcceptedAt string `json:"acceptedAt,omitempty"`
+	CreatedAt  string `json:"createdAt"`
+}
+
+// InvitationResponse 收到的协作邀请
+type InvitationResponse struct {
+	ID           uint   `json:"id"`
+	ArticleID    uint   `json:"articleId"`
+	ArticleTitle string `json:"articleTitle"`
+	Role         string `json:"role"`
+	InvitedBy    uint   `json:"invitedBy"`
+	InviterName  string `json:"inviterName"`
+	CreatedAt    string `json:"createdAt"`
+}
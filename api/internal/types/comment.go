@@ -0,0 +1,82 @@
+package types
+
+// ============== 评论 ==============
+
+type CreateCommentRequest struct {
+	ArticleID uint   `json:"-" path:"id"`
+	ParentID  uint   `json:"parentId,optional"` // 回复的评论，不传表示发表新楼层
+	Body      string `json:"body"`              // Markdown
+}
+
+type UpdateCommentRequest struct {
+	ID   uint   `json:"-" path:"id"`
+	Body string `json:"body"`
+}
+
+type CommentListRequest struct {
+	ID       uint  `json:"-" path:"id"`
+	Page     int   `json:"page,optional" form:"page,optional"`
+	PageSize int   `json:"pageSize,optional" form:"pageSize,optional"`
+	Status   *int8 `json:"status,optional" form:"status,optional"` // 仅作者和管理员可按状态筛选
+}
+
+type CommentRepliesRequest struct {
+	ID       uint `json:"-" path:"id"`
+	Page     int  `json:"page,optional" form:"page,optional"`
+	PageSize int  `json:"pageSize,optional" form:"pageSize,optional"`
+}
+
+type CommentStatusRequest struct {
+	ID     uint `json:"-" path:"id"`
+	Status int8 `json:"status"` // 0:待审核 1:已通过 2:垃圾 3:已隐藏
+}
+
+type CommentResponse struct {
+	ID          uint   `json:"id"`
+	ArticleID   uint   `json:"articleId"`
+	RootID      uint   `json:"rootId"`
+	ParentID    uint   `json:"parentId"`
+	UserID      uint   `json:"userId"`
+	UserName    string `json:"userName"`
+	Avatar      string `json:"avatar"`
+	ReplyToID   uint   `json:"replyToId,omitempty"` // 被回复评论的作者
+	ReplyToName string `json:"replyToName,omitempty"`
+	Body        string `json:"body"`
+	BodyHTML    string `json:"bodyHtml"`
+	Status      int8   `json:"status"`
+	Pinned      bool   `json:"pinned"`
+	Removed     bool   `json:"removed"`
+	CanEdit     bool   `json:"canEdit"`
+	CanDelete   bool   `json:"canDelete"`
+	CreatedAt   string `json:"createdAt"`
+	EditedAt    string `json:"editedAt,omitempty"`
+
+	ReplyCount int64              `json:"replyCount"`        // 仅楼层返回
+	Replies    []*CommentResponse `json:"replies,omitempty"` // 楼层中预览的回复
+}
+
+// ============== 通知 ==============
+
+type NotificationListRequest struct {
+	Page       int  `json:"page,optional" form:"page,optional"`
+	PageSize   int  `json:"pageSize,optional" form:"pageSize,optional"`
+	UnreadOnly bool `json:"unreadOnly,optional" form:"unreadOnly,optional"`
+}
+
+type NotificationResponse struct {
+	ID           uint   `json:"id"`
+	Type         string `json:"type"` // comment/reply
+	ActorID      uint   `json:"actorId"`
+	ActorName    string `json:"actorName"`
+	ArticleID    uint   `json:"articleId"`
+	ArticleTitle string `json:"articleTitle"`
+	CommentID    uint   `json:"commentId"`
+	Excerpt      string `json:"excerpt"`
+	Read         bool   `json:"read"`
+	CreatedAt    string `json:"createdAt"`
+}
+
+type NotificationListResponse struct {
+	PageResponse
+	Unread int64 `json:"unread"`
+}
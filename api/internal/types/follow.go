@@ -0,0 +1,48 @@
+package types
+
+// ============== 关注 ==============
+
+type FollowResponse struct {
+	UserID        uint  `json:"userId"`
+	Following     bool  `json:"following"`
+	FollowerCount int64 `json:"followerCount"`
+}
+
+// UserProfileResponse 用户公开主页
+type UserProfileResponse struct {
+	ID             uint   `json:"id"`
+	Username       string `json:"username"`
+	Nickname       string `json:"nickname"`
+	Avatar         string `json:"avatar"`
+	FollowerCount  int64  `json:"followerCount"`
+	FollowingCount int64  `json:"followingCount"`
+	ArticleCount   int64  `json:"articleCount"`
+	FollowedByMe   bool   `json:"followedByMe"`
+	CreatedAt      string `json:"createdAt"`
+}
+
+type FollowListRequest struct {
+	ID       uint `json:"-" path:"id"`
+	Page     int  `json:"page,optional" form:"page,optional"`
+	PageSize int  `json:"pageSize,optional" form:"pageSize,optional"`
+}
+
+type FollowUserItem struct {
+	ID         uint   `json:"id"`
+	Username   string `json:"username"`
+	Nickname   string `json:"nickname"`
+	Avatar     string `json:"avatar"`
+	FollowedAt string `json:"followedAt"`
+}
+
+// ============== 关注动态 ==============
+
+type FeedRequest struct {
+	Cursor   string `json:"cursor,optional" form:"cursor,optional"` // 上一页返回的 nextCursor，为空时从最新开始
+	PageSize int    `json:"pageSize,optional" form:"pageSize,optional"`
+}
+
+type FeedResponse struct {
+	List       []*ArticleResponse `json:"list"`
+	NextCursor string             `json:"nextCursor"` // 为空表示没有更多
+}
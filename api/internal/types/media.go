@@ -0,0 +1,34 @@
+package types
+
+// ============== 文件上传 ==============
+
+type MediaResponse struct {
+	ID          uint   `json:"id"`
+	URL         string `json:"url"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"contentType"`
+	Size        int64  `json:"size"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	Hash        string `json:"hash"`
+	RefCount    int64  `json:"refCount"` // 引用该文件的文章数
+	CreatedAt   string `json:"createdAt"`
+
+	VariantStatus string                  `json:"variantStatus,omitempty"` // pending/processing/ready/failed，非图片为空
+	Variants      []*MediaVariantResponse `json:"variants,omitempty"`
+	Srcset        string                  `json:"srcset,omitempty"` // 包含原图的响应式图片地址，可直接用于 img 的 srcset
+}
+
+type MediaVariantResponse struct {
+	Name        string `json:"name"` // thumbnail、medium、large
+	URL         string `json:"url"`
+	ContentType string `json:"contentType"`
+	Size        int64  `json:"size"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+}
+
+type MediaFileRequest struct {
+	Shard string `json:"-" path:"shard"`
+	Name  string `json:"-" path:"name"`
+}
@@ -0,0 +1,38 @@
+package types
+
+// ============== 点赞与表情回应 ==============
+
+type LikeResponse struct {
+	ArticleID uint  `json:"articleId"`
+	Liked     bool  `json:"liked"`
+	LikeCount int64 `json:"likeCount"`
+}
+
+type ArticleReactionRequest struct {
+	ID    uint   `json:"-" path:"id"`
+	Emoji string `json:"-" path:"emoji"`
+}
+
+type ReactionSummary struct {
+	Emoji       string `json:"emoji"`
+	Count       int64  `json:"count"`
+	ReactedByMe bool   `json:"reactedByMe"`
+}
+
+// ============== 收藏 ==============
+
+type BookmarkResponse struct {
+	ArticleID  uint `json:"articleId"`
+	Bookmarked bool `json:"bookmarked"`
+}
+
+type BookmarkItem struct {
+	ArticleID    uint   `json:"articleId"`
+	Title        string `json:"title"`
+	Slug         string `json:"slug"`
+	Cover        string `json:"cover"`
+	Summary      string `json:"summary"`
+	AuthorID     uint   `json:"authorId"`
+	AuthorName   string `json:"authorName"`
+	BookmarkedAt string `json:"bookmarkedAt"`
+}
@@ -0,0 +1,52 @@
+package types
+
+// ============== 文章系列 ==============
+
+type CreateSeriesRequest struct {
+	Title       string `json:"title"`
+	Description string `json:"description,optional"`
+}
+
+type UpdateSeriesRequest struct {
+	ID          uint   `json:"-" path:"id"`
+	Title       string `json:"title,optional"`
+	Description string `json:"description,optional"`
+}
+
+type SetSeriesArticlesRequest struct {
+	ID         uint   `json:"-" path:"id"`
+	ArticleIDs []uint `json:"articleIds"` // 按顺序排列，未包含的文章会被移出系列
+}
+
+type SeriesListRequest struct {
+	AuthorID uint `json:"authorId" form:"authorId,optional"`
+}
+
+type SeriesResponse struct {
+	ID           uint                 `json:"id"`
+	Title        string               `json:"title"`
+	Description  string               `json:"description"`
+	AuthorID     uint                 `json:"authorId"`
+	AuthorName   string               `json:"authorName"`
+	ArticleCount int64                `json:"articleCount"`
+	Articles     []*SeriesArticleItem `json:"articles,omitempty"`
+	CreatedAt    string               `json:"createdAt"`
+	UpdatedAt    string               `json:"updatedAt"`
+}
+
+type SeriesArticleItem struct {
+	ID       uint   `json:"id"`
+	Title    string `json:"title"`
+	Status   int8   `json:"status"`
+	Position int    `json:"position"`
+}
+
+// ArticleSeriesNav 文章所在系列及前后篇导航
+type ArticleSeriesNav struct {
+	ID       uint               `json:"id"`
+	Title    string             `json:"title"`
+	Position int                `json:"position"` // 当前文章在系列中的序号（从 1 开始）
+	Total    int                `json:"total"`
+	Prev     *SeriesArticleItem `json:"prev,omitempty"`
+	Next     *SeriesArticleItem `json:"next,omitempty"`
+}
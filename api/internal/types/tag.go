@@ -0,0 +1,46 @@
+package types
+
+// ============== 标签相关 ==============
+
+type TagResponse struct {
+	ID           uint   `json:"id"`
+	Name         string `json:"name"`
+	ArticleCount int64  `json:"articleCount,omitempty"`
+}
+
+type TagListRequest struct {
+	Keyword string `json:"keyword" form:"keyword,optional"`
+}
+
+type RenameTagRequest struct {
+	ID   uint   `json:"-" path:"id"`
+	Name string `json:"name"`
+}
+
+type MergeTagsRequest struct {
+	SourceIDs []uint `json:"sourceIds"` // 被合并的标签
+	TargetID  uint   `json:"targetId"`  // 合并到的标签
+}
+
+// ============== 分类相关 ==============
+
+type CategoryResponse struct {
+	ID           uint   `json:"id"`
+	Name         string `json:"name"`
+	Description  string `json:"description,omitempty"`
+	Sort         int    `json:"sort"`
+	ArticleCount int64  `json:"articleCount,omitempty"`
+}
+
+type CreateCategoryRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,optional"`
+	Sort        int    `json:"sort,optional"`
+}
+
+type UpdateCategoryRequest struct {
+	ID          uint   `json:"-" path:"id"`
+	Name        string `json:"name,optional"`
+	Description string `json:"description,optional"`
+	Sort        *int   `json:"sort,optional"`
+}
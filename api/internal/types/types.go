@@ -37,8 +37,8 @@ type UpdateUserRequest struct {
 // ============== 分页相关 ==============
 
 type PageRequest struct {
-	Page     int `json:"page" form:"page,optional"`
-	PageSize int `json:"pageSize" form:"pageSize,optional"`
+	Page     int `json:"page,optional" form:"page,optional"`
+	PageSize int `json:"pageSize,optional" form:"pageSize,optional"`
 }
 
 type PageResponse struct {
@@ -51,5 +51,11 @@ type PageResponse struct {
 // ============== 通用 ==============
 
 type IDRequest struct {
-	ID uint `json:"id" path:"id"`
+	ID uint `json:"-" path:"id"`
+}
+
+// Visitor 访问者信息，用于浏览统计
+type Visitor struct {
+	IP        string
+	UserAgent string
 }
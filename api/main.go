@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
@@ -8,6 +9,8 @@ import (
 
 	"acupofcoffee/api/internal/config"
 	"acupofcoffee/api/internal/handler"
+	"acupofcoffee/api/internal/job"
+	"acupofcoffee/api/internal/logic"
 	"acupofcoffee/api/internal/svc"
 
 	"github.com/zeromicro/go-zero/core/conf"
@@ -38,6 +41,26 @@ func main() {
 	ctx := svc.NewServiceContext(c)
 	handler.RegisterHandlers(server, ctx)
 
+	// 浏览量和访问统计按实例缓冲，每个实例各自定期写库，退出前写入剩余数据
+	ctx.Views.Start()
+	defer ctx.Views.Stop()
+	ctx.Analytics.Start()
+	defer ctx.Analytics.Stop()
+
+	// 上传图片的缩放在后台处理
+	ctx.Images.Start(func(c context.Context, id uint) error {
+		return logic.NewMediaLogic(c, ctx).ProcessImage(id)
+	})
+	defer ctx.Images.Stop()
+
+	// 后台任务（定时发布等）
+	if c.Job.Enabled {
+		runner := job.NewRunner(ctx)
+		job.RegisterJobs(runner, ctx)
+		runner.Start()
+		defer runner.Stop()
+	}
+
 	fmt.Printf("Starting server at %s:%d...\n", c.Host, c.Port)
 	server.Start()
 }
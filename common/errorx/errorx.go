@@ -55,6 +55,13 @@ func NewUnauthorizedError(msg string) *CodeError {
 	}
 }
 
+func NewForbiddenError(msg string) *CodeError {
+	return &CodeError{
+		Code: CodeForbidden,
+		Msg:  msg,
+	}
+}
+
 func NewNotFoundError(msg string) *CodeError {
 	return &CodeError{
 		Code: CodeNotFound,
@@ -0,0 +1,55 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestEncodeVariantFormats(t *testing.T) {
+	opaque := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	transparent := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			opaque.Set(x, y, color.RGBA{R: uint8(x * 32), G: uint8(y * 32), B: 128, A: 255})
+			transparent.Set(x, y, color.NRGBA{R: uint8(x * 32), G: uint8(y * 32), B: 128, A: uint8(y * 32)})
+		}
+	}
+
+	tests := []struct {
+		name        string
+		img         image.Image
+		contentType string
+		format      string
+	}{
+		{name: "opaque", img: opaque, contentType: "image/jpeg", format: "jpeg"},
+		{name: "transparent", img: transparent, contentType: "image/webp", format: "webp"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			contentType, err := Encode(&buf, tt.img, 82)
+			if err != nil {
+				t.Fatalf("encode: %v", err)
+			}
+			if contentType != tt.contentType {
+				t.Fatalf("content type = %s, want %s", contentType, tt.contentType)
+			}
+
+			img, format, err := image.Decode(&buf)
+			if err != nil || format != tt.format {
+				t.Fatalf("decode: format=%s err=%v", format, err)
+			}
+			if img.Bounds() != tt.img.Bounds() {
+				t.Fatalf("bounds = %v, want %v", img.Bounds(), tt.img.Bounds())
+			}
+			if tt.format == "webp" {
+				// 无损编码，透明度原样保留
+				if got, want := color.NRGBAModel.Convert(img.At(3, 5)), transparent.At(3, 5); got != want {
+					t.Fatalf("pixel = %v, want %v", got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+const (
+	orientationTag = 0x0112
+	exifHeader     = "Exif\x00\x00"
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// VP8X 扩展头中标记含有 EXIF、XMP 块的位
+const (
+	webpFlagEXIF = 0x08
+	webpFlagXMP  = 0x04
+)
+
+// PNG 中可能包含拍摄信息、作者等文字的辅助块
+var pngMetadataChunks = map[string]bool{
+	"eXIf": true,
+	"tEXt": true,
+	"zTXt": true,
+	"iTXt": true,
+	"tIME": true,
+}
+
+// StripMetadata 去掉图片中的 EXIF、XMP、IPTC 和注释等元数据（可能包含拍摄地点、设备信息），
+// 不重新编码像素。JPEG 的方向信息会保留为只含方向的 EXIF，GIF 和无法解析的图片原样返回
+func StripMetadata(contentType string, data []byte) []byte {
+	switch contentType {
+	case "image/jpeg":
+		if out, ok := stripJPEG(data); ok {
+			return out
+		}
+	case "image/png":
+		if out, ok := stripPNG(data); ok {
+			return out
+		}
+	case "image/webp":
+		if out, ok := stripWebP(data); ok {
+			return out
+		}
+	}
+	return data
+}
+
+// Orientation 读取 JPEG 中 EXIF 记录的方向（1-8），没有记录时返回 1
+func Orientation(data []byte) int {
+	o := 1
+	walkJPEG(data, func(marker byte, payload []byte) bool {
+		if marker == 0xE1 && bytes.HasPrefix(payload, []byte(exifHeader)) {
+			if v := exifOrientation(payload[len(exifHeader):]); v != 0 {
+				o = v
+			}
+			return false
+		}
+		return true
+	})
+	return o
+}
+
+// stripJPEG 去掉 APP1（EXIF/XMP）、APP13（IPTC）和注释段，保留 JFIF、ICC 色彩配置等影响显示的段
+func stripJPEG(data []byte) ([]byte, bool) {
+	orientation := Orientation(data)
+
+	out := make([]byte, 0, len(data))
+	out = append(out, 0xFF, 0xD8)
+	inserted := orientation == 1
+	rest, ok := walkJPEG(data, func(marker byte, payload []byte) bool {
+		// 方向信息放在 JFIF 段之后，其他段之前
+		if !inserted && marker != 0xE0 {
+			out = appendSegment(out, 0xE1, minimalExif(orientation))
+			inserted = true
+		}
+		if marker != 0xE1 && marker != 0xED && marker != 0xFE {
+			out = appendSegment(out, marker, payload)
+		}
+		return true
+	})
+	if !ok {
+		return nil, false
+	}
+	return append(out, rest...), true
+}
+
+// walkJPEG 依次回调图像数据之前的各个段，fn 返回 false 时停止。
+// 返回从扫描段（SOS）开始的剩余数据，格式不正确时 ok 为 false
+func walkJPEG(data []byte, fn func(marker byte, payload []byte) bool) (rest []byte, ok bool) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, false
+	}
+	i := 2
+	for i < len(data) {
+		if data[i] != 0xFF {
+			return nil, false
+		}
+		// 段之间可以有多余的填充字节 0xFF
+		for i < len(data) && data[i] == 0xFF {
+			i++
+		}
+		if i >= len(data) {
+			return nil, false
+		}
+		marker := data[i]
+		i++
+		if marker == 0xDA || marker == 0xD9 {
+			return data[i-2:], true
+		}
+		if marker == 0x01 || marker >= 0xD0 && marker <= 0xD7 {
+			continue
+		}
+		if i+2 > len(data) {
+			return nil, false
+		}
+		n := int(binary.BigEndian.Uint16(data[i:]))
+		if n < 2 || i+n > len(data) {
+			return nil, false
+		}
+		if !fn(marker, data[i+2:i+n]) {
+			return nil, true
+		}
+		i += n
+	}
+	return nil, false
+}
+
+func appendSegment(out []byte, marker byte, payload []byte) []byte {
+	out = append(out, 0xFF, marker)
+	out = binary.BigEndian.AppendUint16(out, uint16(len(payload)+2))
+	return append(out, payload...)
+}
+
+// exifOrientation 从 TIFF 结构的第一个 IFD 中读取方向，读不到时返回 0
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 0
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	offset := int(order.Uint32(tiff[4:]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 0
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	for i := 0; i < count; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[entry:]) == orientationTag && order.Uint16(tiff[entry+2:]) == 3 {
+			if v := int(order.Uint16(tiff[entry+8:])); v >= 1 && v <= 8 {
+				return v
+			}
+			return 0
+		}
+	}
+	return 0
+}
+
+// minimalExif 只包含方向一项的 EXIF
+func minimalExif(orientation int) []byte {
+	b := []byte(exifHeader + "MM\x00\x2a")
+	b = binary.BigEndian.AppendUint32(b, 8) // 第一个 IFD 紧跟在文件头之后
+	b = binary.BigEndian.AppendUint16(b, 1)
+	b = binary.BigEndian.AppendUint16(b, orientationTag)
+	b = binary.BigEndian.AppendUint16(b, 3) // SHORT
+	b = binary.BigEndian.AppendUint32(b, 1)
+	b = binary.BigEndian.AppendUint16(b, uint16(orientation))
+	b = binary.BigEndian.AppendUint16(b, 0)
+	return binary.BigEndian.AppendUint32(b, 0) // 没有下一个 IFD
+}
+
+// stripPNG 去掉文字、时间和 EXIF 辅助块
+func stripPNG(data []byte) ([]byte, bool) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, false
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, pngSignature...)
+	i := len(pngSignature)
+	for i < len(data) {
+		// 长度、类型、数据、CRC
+		if i+8 > len(data) {
+			return nil, false
+		}
+		n := int(binary.BigEndian.Uint32(data[i:]))
+		end := i + 12 + n
+		if n < 0 || end > len(data) || end < i {
+			return nil, false
+		}
+		chunk := string(data[i+4 : i+8])
+		if !pngMetadataChunks[chunk] {
+			out = append(out, data[i:end]...)
+		}
+		i = end
+		if chunk == "IEND" {
+			return out, true
+		}
+	}
+	return nil, false
+}
+
+// stripWebP 去掉 RIFF 容器中的 EXIF 和 XMP 块，并清除扩展头中对应的标记。
+// WebP 规范中解码器不按 EXIF 方向旋转，因此不需要保留方向
+func stripWebP(data []byte) ([]byte, bool) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, false
+	}
+	size := int(binary.LittleEndian.Uint32(data[4:]))
+	if size < 4 || 8+size > len(data) {
+		return nil, false
+	}
+	data = data[:8+size]
+
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:12]...)
+	for i := 12; i < len(data); {
+		// 类型、长度、数据，数据长度为奇数时补一个字节
+		if i+8 > len(data) {
+			return nil, false
+		}
+		n := int(binary.LittleEndian.Uint32(data[i+4:]))
+		end := i + 8 + n + n&1
+		if end > len(data) || end < i {
+			return nil, false
+		}
+		switch chunk := string(data[i : i+4]); chunk {
+		case "EXIF", "XMP ":
+		case "VP8X":
+			start := len(out)
+			out = append(out, data[i:end]...)
+			if n > 0 {
+				out[start+8] &^= webpFlagEXIF | webpFlagXMP
+			}
+		default:
+			out = append(out, data[i:end]...)
+		}
+		i = end
+	}
+
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(out)-8))
+	return out, true
+}
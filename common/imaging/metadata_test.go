@@ -0,0 +1,63 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"image"
+	"testing"
+)
+
+// 1x1 的无损 WebP
+const tinyWebP = "UklGRhoAAABXRUJQVlA4TA0AAAAvAAAAEAcQERGIiP4HAA=="
+
+func riffChunk(fourCC string, payload []byte) []byte {
+	b := append([]byte(fourCC), binary.LittleEndian.AppendUint32(nil, uint32(len(payload)))...)
+	b = append(b, payload...)
+	if len(payload)%2 == 1 {
+		b = append(b, 0)
+	}
+	return b
+}
+
+func TestStripWebPMetadata(t *testing.T) {
+	simple, err := base64.StdEncoding.DecodeString(tinyWebP)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 扩展格式：VP8X 头标记含有 EXIF 和 XMP，图像数据前后各带一个元数据块
+	vp8x := make([]byte, 10)
+	vp8x[0] = webpFlagEXIF | webpFlagXMP
+	body := []byte("WEBP")
+	body = append(body, riffChunk("VP8X", vp8x)...)
+	body = append(body, riffChunk("XMP ", []byte("<x:xmpmeta>gps</x:xmpmeta>"))...)
+	body = append(body, simple[12:]...)
+	body = append(body, riffChunk("EXIF", []byte("MM\\x00\\x2aGPS"))...)
+	data := append([]byte("RIFF"), binary.LittleEndian.AppendUint32(nil, uint32(len(body)))...)
+	data = append(data, body...)
+
+	if _, _, err := image.DecodeConfig(bytes.NewReader(data)); err != nil {
+		t.Fatalf("decode input: %v", err)
+	}
+
+	out := StripMetadata("image/webp", data)
+	if bytes.Contains(out, []byte("EXIF")) || bytes.Contains(out, []byte("XMP ")) || bytes.Contains(out, []byte("GPS")) {
+		t.Fatalf("metadata left in output: %q", out)
+	}
+	if got := int(binary.LittleEndian.Uint32(out[4:])); got != len(out)-8 {
+		t.Fatalf("riff size = %d, want %d", got, len(out)-8)
+	}
+	if flags := out[20]; flags&(webpFlagEXIF|webpFlagXMP) != 0 {
+		t.Fatalf("vp8x flags = %#x, metadata flags not cleared", flags)
+	}
+	c, format, err := image.DecodeConfig(bytes.NewReader(out))
+	if err != nil || format != "webp" || c.Width != 1 || c.Height != 1 {
+		t.Fatalf("decode output: %v %s %dx%d", err, format, c.Width, c.Height)
+	}
+
+	// 没有元数据的简单格式原样保留
+	if out := StripMetadata("image/webp", simple); !bytes.Equal(out, simple) {
+		t.Fatalf("simple webp changed: %q", out)
+	}
+}
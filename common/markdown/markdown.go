@@ -0,0 +1,35 @@
+package markdown
+
+import (
+	"bytes"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+var (
+	// 不开启 html.WithUnsafe，原文中的 HTML 不会被输出
+	renderer = goldmark.New(
+		goldmark.WithExtensions(extension.Strikethrough, extension.Linkify, extension.Table),
+	)
+
+	// 用户输入的内容，渲染后再按白名单过滤一遍
+	policy = newPolicy()
+)
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.RequireNoFollowOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	return p
+}
+
+// Render 将 Markdown 渲染为过滤后的 HTML
+func Render(source string) (string, error) {
+	var buf bytes.Buffer
+	if err := renderer.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+	return policy.Sanitize(buf.String()), nil
+}
@@ -0,0 +1,177 @@
+package richtext
+
+import (
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// 差异操作
+const (
+	OpEqual  = "equal"
+	OpInsert = "insert"
+	OpDelete = "delete"
+	OpModify = "modify"
+)
+
+// Segment 行内差异片段
+type Segment struct {
+	Op   string
+	Text string
+}
+
+// BlockChange 块级差异，Op 为 modify 时 Inline 给出行内的增删
+type BlockChange struct {
+	Op     string
+	Old    *Block
+	New    *Block
+	Inline []Segment
+}
+
+// DiffText 字符级比较两段文本，并按语义合并零碎的差异
+func DiffText(a, b string) []Segment {
+	dmp := diffmatchpatch.New()
+	diffs := dmp.DiffCleanupSemantic(dmp.DiffMain(a, b, false))
+
+	segments := make([]Segment, 0, len(diffs))
+	for _, d := range diffs {
+		segments = append(segments, Segment{Op: diffOp(d.Type), Text: d.Text})
+	}
+	return segments
+}
+
+// DiffBlocks 比较两组块。先以整块为单位求最小编辑序列，
+// 再将相邻的删除/插入中类型相同的块配对为修改，并给出行内差异。
+func DiffBlocks(a, b []Block) []BlockChange {
+	ra, rb := blocksToRunes(a, b)
+
+	dmp := diffmatchpatch.New()
+	diffs := dmp.DiffMainRunes(ra, rb, false)
+
+	var changes []BlockChange
+	var deleted, inserted []Block
+	ia, ib := 0, 0
+
+	flush := func() {
+		changes = append(changes, pairChanges(deleted, inserted)...)
+		deleted, inserted = nil, nil
+	}
+
+	for _, d := range diffs {
+		n := len([]rune(d.Text))
+		switch d.Type {
+		case diffmatchpatch.DiffEqual:
+			flush()
+			for i := 0; i < n; i++ {
+				changes = append(changes, BlockChange{Op: OpEqual, Old: &a[ia], New: &b[ib]})
+				ia++
+				ib++
+			}
+		case diffmatchpatch.DiffDelete:
+			deleted = append(deleted, a[ia:ia+n]...)
+			ia += n
+		case diffmatchpatch.DiffInsert:
+			inserted = append(inserted, b[ib:ib+n]...)
+			ib += n
+		}
+	}
+	flush()
+
+	return changes
+}
+
+// UnifiedDiff 生成统一格式的纯文本差异
+func UnifiedDiff(a, b []Block, fromName, toName string) string {
+	diff := difflib.UnifiedDiff{
+		A:        difflib.SplitLines(Render(a)),
+		B:        difflib.SplitLines(Render(b)),
+		FromFile: fromName,
+		ToFile:   toName,
+		Context:  3,
+	}
+	text, err := difflib.GetUnifiedDiffString(diff)
+	if err != nil {
+		return ""
+	}
+	return text
+}
+
+// blocksToRunes 为每个不同的块分配一个字符，便于按块比较
+func blocksToRunes(a, b []Block) ([]rune, []rune) {
+	index := make(map[string]rune)
+	next := rune(0xE000) // 从私有区开始，避开代理区
+
+	encode := func(blocks []Block) []rune {
+		runes := make([]rune, len(blocks))
+		for i, block := range blocks {
+			key := block.Key()
+			r, ok := index[key]
+			if !ok {
+				r = next
+				index[key] = r
+				next++
+			}
+			runes[i] = r
+		}
+		return runes
+	}
+
+	return encode(a), encode(b)
+}
+
+// pairChanges 将一段连续的删除和插入配对：删除的块与其后第一个类型相同的插入块视为修改，
+// 中间跳过的插入块照常输出
+func pairChanges(deleted, inserted []Block) []BlockChange {
+	var changes []BlockChange
+	j := 0
+	for i := range deleted {
+		old := &deleted[i]
+		match := -1
+		if old.Type != BlockImage && old.Type != BlockRule {
+			for k := j; k < len(inserted); k++ {
+				if inserted[k].Type == old.Type {
+					match = k
+					break
+				}
+			}
+		}
+		if match < 0 {
+			changes = append(changes, BlockChange{Op: OpDelete, Old: old})
+			continue
+		}
+
+		for ; j < match; j++ {
+			changes = append(changes, BlockChange{Op: OpInsert, New: &inserted[j]})
+		}
+		cur := &inserted[j]
+		changes = append(changes, BlockChange{Op: OpModify, Old: old, New: cur, Inline: DiffText(old.Text, cur.Text)})
+		j++
+	}
+	for ; j < len(inserted); j++ {
+		changes = append(changes, BlockChange{Op: OpInsert, New: &inserted[j]})
+	}
+	return changes
+}
+
+func diffOp(t diffmatchpatch.Operation) string {
+	switch t {
+	case diffmatchpatch.DiffInsert:
+		return OpInsert
+	case diffmatchpatch.DiffDelete:
+		return OpDelete
+	default:
+		return OpEqual
+	}
+}
+
+// ChangedChars 统计差异片段中新增和删除的字符数
+func ChangedChars(segments []Segment) (added, removed int) {
+	for _, s := range segments {
+		switch s.Op {
+		case OpInsert:
+			added += len([]rune(s.Text))
+		case OpDelete:
+			removed += len([]rune(s.Text))
+		}
+	}
+	return added, removed
+}
@@ -0,0 +1,84 @@
+package richtext
+
+import (
+	"html"
+	"strconv"
+	"strings"
+)
+
+// HTML 将块列表输出为 HTML，文本全部转义，连续的列表项按缩进层级合并为嵌套列表
+func HTML(blocks []Block) string {
+	var sb strings.Builder
+	depth := -1 // 当前打开的列表层级，-1 表示不在列表中
+
+	closeLists := func(to int) {
+		for ; depth > to; depth-- {
+			sb.WriteString("</li></ul>")
+		}
+	}
+
+	for _, b := range blocks {
+		if b.Type != BlockListItem {
+			closeLists(-1)
+		}
+
+		switch b.Type {
+		case BlockHeading:
+			level := b.Level
+			if level < 1 {
+				level = 1
+			}
+			if level > 6 {
+				level = 6
+			}
+			tag := "h" + strconv.Itoa(level)
+			sb.WriteString("<" + tag + ">" + escapeText(b.Text) + "</" + tag + ">")
+		case BlockListItem:
+			level := b.Level
+			if level < 0 {
+				level = 0
+			}
+			switch {
+			case level > depth:
+				// 跳级缩进时补齐中间层级
+				for depth < level {
+					sb.WriteString("<ul><li>")
+					depth++
+				}
+			case level < depth:
+				closeLists(level)
+				sb.WriteString("</li><li>")
+			default:
+				sb.WriteString("</li><li>")
+			}
+			sb.WriteString(escapeText(b.Text))
+		case BlockBlockquote:
+			sb.WriteString("<blockquote><p>" + escapeText(b.Text) + "</p></blockquote>")
+		case BlockCode:
+			sb.WriteString("<pre><code>" + html.EscapeString(b.Text) + "</code></pre>")
+		case BlockImage:
+			if isSafeURL(b.Text) {
+				sb.WriteString(`<img src="` + html.EscapeString(b.Text) + `" alt="">`)
+			}
+		case BlockRule:
+			sb.WriteString("<hr>")
+		default:
+			sb.WriteString("<p>" + escapeText(b.Text) + "</p>")
+		}
+	}
+	closeLists(-1)
+
+	return sb.String()
+}
+
+// escapeText 转义文本，段内换行输出为 <br>
+func escapeText(s string) string {
+	return strings.ReplaceAll(html.EscapeString(s), "\n", "<br>")
+}
+
+// isSafeURL 图片地址只允许 http(s) 和站内相对路径
+func isSafeURL(u string) bool {
+	lower := strings.ToLower(strings.TrimSpace(u))
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") ||
+		strings.HasPrefix(lower, "/") && !strings.HasPrefix(lower, "//")
+}
@@ -0,0 +1,303 @@
+package richtext
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+
+	"acupofcoffee/common/utils"
+)
+
+// 块类型
+const (
+	BlockParagraph  = "paragraph"
+	BlockHeading    = "heading"
+	BlockListItem   = "list-item"
+	BlockBlockquote = "blockquote"
+	BlockCode       = "code"
+	BlockImage      = "image"
+	BlockRule       = "rule"
+)
+
+// Block 文档中的一个块级元素
+type Block struct {
+	Type  string
+	Level int // 标题级别或列表缩进
+	Text  string
+}
+
+// Key 块的比较键，类型和内容都相同才视为同一个块
+func (b Block) Key() string {
+	return b.Type + "\x00" + string(rune('0'+b.Level)) + "\x00" + b.Text
+}
+
+// String 以类 Markdown 的纯文本形式输出块
+func (b Block) String() string {
+	switch b.Type {
+	case BlockHeading:
+		level := b.Level
+		if level < 1 {
+			level = 1
+		}
+		return strings.Repeat("#", level) + " " + b.Text
+	case BlockListItem:
+		return strings.Repeat("  ", b.Level) + "- " + b.Text
+	case BlockBlockquote:
+		return "> " + b.Text
+	case BlockCode:
+		return "```\n" + b.Text + "\n```"
+	case BlockImage:
+		return "![](" + b.Text + ")"
+	case BlockRule:
+		return "---"
+	default:
+		return b.Text
+	}
+}
+
+// Parse 将文章内容解析为块列表。
+// 支持 Quill Delta（{"ops":[...]} 或 ops 数组）和 ProseMirror/TipTap（{"type":"doc"}），
+// 无法识别的内容按纯文本逐行处理。
+func Parse(content string) []Block {
+	trimmed := strings.TrimSpace(content)
+	if trimmed == "" {
+		return nil
+	}
+
+	switch trimmed[0] {
+	case '{':
+		var doc struct {
+			Ops  []deltaOp `json:"ops"`
+			Type string    `json:"type"`
+		}
+		if err := json.Unmarshal([]byte(trimmed), &doc); err == nil {
+			if doc.Ops != nil {
+				return parseDelta(doc.Ops)
+			}
+			if doc.Type != "" {
+				var node pmNode
+				if err := json.Unmarshal([]byte(trimmed), &node); err == nil {
+					return parseProseMirror(&node)
+				}
+			}
+		}
+	case '[':
+		var ops []deltaOp
+		if err := json.Unmarshal([]byte(trimmed), &ops); err == nil {
+			return parseDelta(ops)
+		}
+	}
+
+	return parsePlain(content)
+}
+
+// Render 将块列表输出为纯文本，每个块之间以换行分隔
+func Render(blocks []Block) string {
+	lines := make([]string, len(blocks))
+	for i, b := range blocks {
+		lines[i] = b.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// PlainText 提取块中的文字，用空白连接，图片、分割线和代码块不计入
+func PlainText(blocks []Block) string {
+	parts := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		switch b.Type {
+		case BlockImage, BlockRule, BlockCode:
+			continue
+		}
+		if text := strings.Join(strings.Fields(b.Text), " "); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// Summary 生成摘要：取第一个不少于 minParagraph 个字符的段落（跳过标题、图片、代码和过短的段落），
+// 按句子截断到 max 个字符；没有这样的段落时使用全文
+func Summary(blocks []Block, max, minParagraph int) string {
+	for _, b := range blocks {
+		if b.Type != BlockParagraph && b.Type != BlockBlockquote {
+			continue
+		}
+		text := strings.Join(strings.Fields(b.Text), " ")
+		if utf8.RuneCountInString(text) >= minParagraph {
+			return utils.TruncateSentence(text, max)
+		}
+	}
+	return utils.TruncateSentence(PlainText(blocks), max)
+}
+
+func parsePlain(content string) []Block {
+	var blocks []Block
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		blocks = append(blocks, Block{Type: BlockParagraph, Text: line})
+	}
+	return blocks
+}
+
+// ============== Quill Delta ==============
+
+type deltaOp struct {
+	Insert     json.RawMessage        `json:"insert"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+// parseDelta 按换行切分文本，换行符上的属性决定该行的块类型
+func parseDelta(ops []deltaOp) []Block {
+	var blocks []Block
+	var line strings.Builder
+
+	for _, op := range ops {
+		if len(op.Insert) == 0 {
+			continue
+		}
+
+		var text string
+		if err := json.Unmarshal(op.Insert, &text); err != nil {
+			// 嵌入对象（图片、分割线等）
+			var embed map[string]interface{}
+			if json.Unmarshal(op.Insert, &embed) != nil {
+				continue
+			}
+			if src, ok := embed["image"].(string); ok {
+				blocks = appendLine(blocks, &line, nil)
+				blocks = append(blocks, Block{Type: BlockImage, Text: src})
+			} else if _, ok := embed["divider"]; ok {
+				blocks = appendLine(blocks, &line, nil)
+				blocks = append(blocks, Block{Type: BlockRule})
+			}
+			continue
+		}
+
+		parts := strings.Split(text, "\n")
+		for i, part := range parts {
+			line.WriteString(part)
+			if i < len(parts)-1 {
+				blocks = appendLine(blocks, &line, op.Attributes)
+			}
+		}
+	}
+
+	// Delta 末尾通常以换行结束，残留文本按段落处理
+	return appendLine(blocks, &line, nil)
+}
+
+func appendLine(blocks []Block, line *strings.Builder, attrs map[string]interface{}) []Block {
+	text := line.String()
+	line.Reset()
+
+	block := Block{Type: BlockParagraph, Text: text}
+	switch {
+	case attrs["header"] != nil:
+		block.Type = BlockHeading
+		block.Level = intAttr(attrs["header"])
+	case attrs["list"] != nil:
+		block.Type = BlockListItem
+		block.Level = intAttr(attrs["indent"])
+	case attrs["blockquote"] != nil:
+		block.Type = BlockBlockquote
+	case attrs["code-block"] != nil:
+		block.Type = BlockCode
+	}
+
+	// 连续的代码行合并为一个代码块
+	if block.Type == BlockCode && len(blocks) > 0 && blocks[len(blocks)-1].Type == BlockCode {
+		blocks[len(blocks)-1].Text += "\n" + text
+		return blocks
+	}
+
+	if block.Type == BlockParagraph && strings.TrimSpace(text) == "" {
+		return blocks
+	}
+	return append(blocks, block)
+}
+
+func intAttr(v interface{}) int {
+	if f, ok := v.(float64); ok {
+		return int(f)
+	}
+	return 0
+}
+
+// ============== ProseMirror / TipTap ==============
+
+type pmNode struct {
+	Type    string                 `json:"type"`
+	Attrs   map[string]interface{} `json:"attrs"`
+	Content []pmNode               `json:"content"`
+	Text    string                 `json:"text"`
+}
+
+func parseProseMirror(doc *pmNode) []Block {
+	var blocks []Block
+	walkProseMirror(doc, "", 0, &blocks)
+	return blocks
+}
+
+// walkProseMirror 遍历节点树，container 为外层容器对应的块类型
+func walkProseMirror(node *pmNode, container string, depth int, blocks *[]Block) {
+	switch node.Type {
+	case "paragraph":
+		text := inlineText(node)
+		if container == "" && strings.TrimSpace(text) == "" {
+			return
+		}
+		typ := container
+		if typ == "" {
+			typ = BlockParagraph
+		}
+		*blocks = append(*blocks, Block{Type: typ, Level: depth, Text: text})
+	case "heading":
+		*blocks = append(*blocks, Block{Type: BlockHeading, Level: intAttr(node.Attrs["level"]), Text: inlineText(node)})
+	case "code_block", "codeBlock":
+		*blocks = append(*blocks, Block{Type: BlockCode, Text: inlineText(node)})
+	case "image":
+		src, _ := node.Attrs["src"].(string)
+		*blocks = append(*blocks, Block{Type: BlockImage, Text: src})
+	case "horizontal_rule", "horizontalRule":
+		*blocks = append(*blocks, Block{Type: BlockRule})
+	case "blockquote":
+		for i := range node.Content {
+			walkProseMirror(&node.Content[i], BlockBlockquote, 0, blocks)
+		}
+	case "list_item", "listItem", "taskItem":
+		// 嵌套列表的缩进层级加一
+		for i := range node.Content {
+			child := &node.Content[i]
+			if child.Type == "paragraph" {
+				walkProseMirror(child, BlockListItem, depth, blocks)
+			} else {
+				walkProseMirror(child, BlockListItem, depth+1, blocks)
+			}
+		}
+	default:
+		// doc、bullet_list 等容器节点
+		for i := range node.Content {
+			walkProseMirror(&node.Content[i], container, depth, blocks)
+		}
+	}
+}
+
+func inlineText(node *pmNode) string {
+	var sb strings.Builder
+	var walk func(n *pmNode)
+	walk = func(n *pmNode) {
+		switch n.Type {
+		case "text":
+			sb.WriteString(n.Text)
+		case "hard_break", "hardBreak":
+			sb.WriteString("\n")
+		}
+		for i := range n.Content {
+			walk(&n.Content[i])
+		}
+	}
+	walk(node)
+	return sb.String()
+}
@@ -0,0 +1,49 @@
+package richtext
+
+import "testing"
+
+func TestSummary(t *testing.T) {
+	tests := []struct {
+		name   string
+		blocks []Block
+		max    int
+		want   string
+	}{
+		{
+			name: "skips headings and short paragraphs",
+			blocks: []Block{
+				{Type: BlockHeading, Level: 1, Text: "标题不进入摘要，即使它足够长足够长"},
+				{Type: BlockParagraph, Text: "短段落。"},
+				{Type: BlockParagraph, Text: "正文第一句话。第二句话比较长，会被截断掉。"},
+			},
+			max:  12,
+			want: "正文第一句话。",
+		},
+		{
+			name: "blockquote counts",
+			blocks: []Block{
+				{Type: BlockImage, Text: "/uploads/a.jpg"},
+				{Type: BlockBlockquote, Text: "引用的内容足够长。"},
+			},
+			max:  20,
+			want: "引用的内容足够长。",
+		},
+		{
+			name: "falls back to full text",
+			blocks: []Block{
+				{Type: BlockHeading, Level: 2, Text: "Intro"},
+				{Type: BlockCode, Text: "fmt.Println()"},
+				{Type: BlockListItem, Text: "one   two"},
+			},
+			max:  20,
+			want: "Intro one two",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Summary(tt.blocks, tt.max, 8); got != tt.want {
+				t.Fatalf("Summary = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Package sitemap 输出 sitemaps.org 协议的站点地图和站点地图索引
+package sitemap
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+const (
+	ContentType = "application/xml; charset=utf-8"
+	MaxURLs     = 50000 // 协议规定单个站点地图最多包含的地址数
+
+	namespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+)
+
+// Entry 站点地图中的一个地址，或索引中的一个站点地图
+type Entry struct {
+	Loc     string
+	LastMod time.Time
+}
+
+type urlSet struct {
+	XMLName xml.Name `xml:"urlset"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	URLs    []entry  `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name `xml:"sitemapindex"`
+	XMLNS    string   `xml:"xmlns,attr"`
+	Sitemaps []entry  `xml:"sitemap"`
+}
+
+type entry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// URLSet 输出站点地图
+func URLSet(entries []Entry) ([]byte, error) {
+	return marshal(urlSet{XMLNS: namespace, URLs: toEntries(entries)})
+}
+
+// Index 输出站点地图索引
+func Index(entries []Entry) ([]byte, error) {
+	return marshal(sitemapIndex{XMLNS: namespace, Sitemaps: toEntries(entries)})
+}
+
+func toEntries(entries []Entry) []entry {
+	out := make([]entry, len(entries))
+	for i, e := range entries {
+		out[i] = entry{Loc: e.Loc}
+		if !e.LastMod.IsZero() {
+			out[i].LastMod = e.LastMod.UTC().Format(time.RFC3339)
+		}
+	}
+	return out
+}
+
+func marshal(v interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
@@ -6,6 +6,8 @@ import (
 	"regexp"
 	"strings"
 	"unicode"
+
+	"github.com/gosimple/slug"
 )
 
 // GenerateRandomString 生成随机字符串
@@ -69,3 +71,92 @@ func MaskEmail(email string) string {
 	}
 	return name[:2] + "***@" + parts[1]
 }
+
+// Slugify 将标题转换为 URL 友好的 slug（中文转为拼音），超长时按单词截断
+func Slugify(title string, maxLength int) string {
+	s := slug.Make(title)
+	if maxLength > 0 && len(s) > maxLength {
+		s = s[:maxLength]
+		if i := strings.LastIndex(s, "-"); i > 0 {
+			s = s[:i]
+		}
+		s = strings.Trim(s, "-")
+	}
+	return s
+}
+
+// IsSlug 判断字符串是否为合法的 slug（小写字母、数字和连字符）
+func IsSlug(s string) bool {
+	return slug.IsSlug(s)
+}
+
+// TruncateRunes 按字符截断字符串，超出部分以省略号代替
+func TruncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max]) + "…"
+}
+
+// TruncateSentence 按句子截断到不超过 max 个字符：优先在最后一个完整句子处结束，
+// 前半段没有句末标点时退回到逗号或空格处并加省略号，避免截断词语
+func TruncateSentence(s string, max int) string {
+	s = strings.TrimSpace(s)
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	if max <= 1 {
+		return "…"
+	}
+
+	// 截断点太靠前时摘要过短，只在后半段内寻找
+	min := max / 2
+	for i := max - 1; i >= min; i-- {
+		if !isSentenceEnd(runes, i) {
+			continue
+		}
+		// 句末标点后的引号、括号一并保留
+		end := i + 1
+		for end < max && isClosingPunct(runes[end]) {
+			end++
+		}
+		return strings.TrimSpace(string(runes[:end]))
+	}
+
+	for i := max - 1; i >= min; i-- {
+		if isClauseBreak(runes[i]) {
+			return strings.TrimRightFunc(string(runes[:i]), isTrailingPunct) + "…"
+		}
+	}
+	return string(runes[:max-1]) + "…"
+}
+
+// isSentenceEnd 句末标点，分号只算分句。英文句点后面（引号、括号之后）必须是空白，
+// 避免在小数和网址中间截断
+func isSentenceEnd(runes []rune, i int) bool {
+	switch runes[i] {
+	case '。', '！', '？', '…', '!', '?':
+		return true
+	case '.':
+		j := i + 1
+		for j < len(runes) && isClosingPunct(runes[j]) {
+			j++
+		}
+		return j < len(runes) && unicode.IsSpace(runes[j])
+	}
+	return false
+}
+
+func isClosingPunct(r rune) bool {
+	return strings.ContainsRune("”’」』）》】)]\"'", r)
+}
+
+func isClauseBreak(r rune) bool {
+	return strings.ContainsRune("，、：；,:;", r) || unicode.IsSpace(r)
+}
+
+func isTrailingPunct(r rune) bool {
+	return isClauseBreak(r) || strings.ContainsRune("（《「『“‘([-—", r)
+}
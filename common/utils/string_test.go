@@ -0,0 +1,35 @@
+package utils
+
+import "testing"
+
+func TestTruncateSentence(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		max  int
+		want string
+	}{
+		{name: "short", s: "  短文本。 ", max: 10, want: "短文本。"},
+		{name: "cjk sentence", s: "第一句话说完了。第二句话还很长很长很长", max: 12, want: "第一句话说完了。"},
+		{name: "cjk exclamation", s: "太好了！接下来是很长很长的内容", max: 7, want: "太好了！"},
+		{name: "closing quote kept", s: "他说：“走吧。”然后我们出发去了很远的地方", max: 12, want: "他说：“走吧。”"},
+		{name: "closing bracket kept", s: "见附录（已更新。）后面还有很多很多内容", max: 14, want: "见附录（已更新。）"},
+		{name: "english sentence", s: "It works. Then it keeps going for a while", max: 16, want: "It works."},
+		{name: "english quote", s: `He said "go." Then we left the house`, max: 20, want: `He said "go."`},
+		{name: "decimal", s: "Pi is about 3.14159 and more digits follow", max: 20, want: "Pi is about 3.14159…"},
+		{name: "url", s: "Visit example.com/a.b for the full documentation", max: 24, want: "Visit example.com/a.b…"},
+		{name: "semicolon is clause break", s: "前半句内容比较长；后半句内容也比较长", max: 12, want: "前半句内容比较长…"},
+		{name: "english semicolon", s: "first part here; second part here too", max: 17, want: "first part here…"},
+		{name: "comma fallback", s: "一段没有句号的文字，后面接着很多很多字", max: 14, want: "一段没有句号的文字…"},
+		{name: "opening quote trimmed", s: "前面的内容“ 后面的内容很长很长", max: 8, want: "前面的内容…"},
+		{name: "hard cut", s: "一二三四五六七八九十一二三四五", max: 8, want: "一二三四五六七…"},
+		{name: "tiny max", s: "一二三", max: 1, want: "…"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TruncateSentence(tt.s, tt.max); got != tt.want {
+				t.Fatalf("TruncateSentence(%q, %d) = %q, want %q", tt.s, tt.max, got, tt.want)
+			}
+		})
+	}
+}
@@ -43,7 +43,9 @@ func GetEndOfDay(t time.Time) time.Time {
 	return time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 59, 999999999, t.Location())
 }
 
-// DaysBetween 计算两个日期之间的天数
+// DaysBetween 计算两个日期之间相差的自然日天数，按各自时区的日期计算，不受夏令时影响
 func DaysBetween(start, end time.Time) int {
-	return int(end.Sub(start).Hours() / 24)
+	s := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	e := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+	return int(e.Sub(s).Hours() / 24)
 }
@@ -0,0 +1,26 @@
+package utils
+
+import "strings"
+
+// 常见爬虫、监控和命令行工具的 User-Agent 特征
+var botKeywords = []string{
+	"bot", "spider", "crawl", "slurp", "archiver", "fetcher",
+	"facebookexternalhit", "embedly", "preview", "monitor", "uptime",
+	"headless", "phantomjs", "lighthouse", "pingdom",
+	"curl", "wget", "httpie", "python-requests", "python-urllib", "go-http-client",
+	"okhttp", "java/", "libwww", "axios", "node-fetch", "postman",
+}
+
+// IsBot 根据 User-Agent 判断是否为爬虫或脚本请求，空 User-Agent 也视为爬虫
+func IsBot(userAgent string) bool {
+	ua := strings.ToLower(strings.TrimSpace(userAgent))
+	if ua == "" {
+		return true
+	}
+	for _, keyword := range botKeywords {
+		if strings.Contains(ua, keyword) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// ArticleDailyStat 文章按天汇总的访问数据，日期按配置的统计时区划分
+type ArticleDailyStat struct {
+	ID             uint      `gorm:"primarykey" json:"id"`
+	ArticleID      uint      `gorm:"uniqueIndex:idx_article_daily_stats_day,priority:1;not null" json:"articleId"`
+	Day            string    `gorm:"uniqueIndex:idx_article_daily_stats_day,priority:2;type:varchar(10);not null" json:"day"` // 2006-01-02
+	Views          int64     `gorm:"default:0" json:"views"`
+	Visitors       int64     `gorm:"default:0;comment:当天独立访客数" json:"visitors"`
+	ReadCount      int64     `gorm:"default:0;comment:上报了阅读进度的访客数" json:"readCount"`
+	ReadDepthTotal int64     `gorm:"default:0;comment:阅读深度百分比之和" json:"readDepthTotal"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+func (ArticleDailyStat) TableName() string {
+	return "article_daily_stats"
+}
+
+// ArticleDailyReferrer 文章按天汇总的来源域名，直接访问的域名为空
+type ArticleDailyReferrer struct {
+	ID        uint   `gorm:"primarykey" json:"id"`
+	ArticleID uint   `gorm:"uniqueIndex:idx_article_daily_referrers_domain,priority:1;not null" json:"articleId"`
+	Day       string `gorm:"uniqueIndex:idx_article_daily_referrers_domain,priority:2;type:varchar(10);not null" json:"day"`
+	Domain    string `gorm:"uniqueIndex:idx_article_daily_referrers_domain,priority:3;type:varchar(255);not null" json:"domain"`
+	Visits    int64  `gorm:"default:0" json:"visits"`
+}
+
+func (ArticleDailyReferrer) TableName() string {
+	return "article_daily_referrers"
+}
+
+// ArticleDailyVisitor 当天访客的去重记录，只保存访客标识的哈希，过期后由后台任务清理
+type ArticleDailyVisitor struct {
+	ID        uint   `gorm:"primarykey" json:"id"`
+	ArticleID uint   `gorm:"uniqueIndex:idx_article_daily_visitors_visitor,priority:1;not null" json:"articleId"`
+	Day       string `gorm:"uniqueIndex:idx_article_daily_visitors_visitor,priority:2;type:varchar(10);index;not null" json:"day"`
+	Visitor   string `gorm:"uniqueIndex:idx_article_daily_visitors_visitor,priority:3;type:char(16);not null" json:"visitor"`
+	Reported  bool   `gorm:"default:false;comment:当天是否已上报阅读进度" json:"reported"`
+}
+
+func (ArticleDailyVisitor) TableName() string {
+	return "article_daily_visitors"
+}
@@ -1,19 +1,34 @@
 package model
 
+import "time"
+
 // Article 文章模型
 type Article struct {
 	BaseModel
 	Title      string `gorm:"type:varchar(255);not null;index" json:"title"`
-	Content    string `gorm:"type:longtext" json:"content"`     // 富文本内容（JSON字符串）
-	ContentRaw string `gorm:"type:longtext" json:"contentRaw"`  // 纯文本内容（用于搜索）
-	Cover      string `gorm:"type:varchar(500)" json:"cover"`   // 封面图
-	Summary    string `gorm:"type:varchar(500)" json:"summary"` // 摘要
-	AuthorID   uint   `gorm:"index;not null" json:"authorId"`   // 作者ID
+	Slug       string `gorm:"type:varchar(200);index" json:"slug"`
+	Content    string `gorm:"type:longtext" json:"content"`                                                  // 富文本内容（JSON字符串）
+	ContentRaw string `gorm:"type:longtext" json:"contentRaw"`                                               // 纯文本内容（用于搜索）
+	Cover      string `gorm:"type:varchar(500)" json:"cover"`                                                // 封面图
+	Summary    string `gorm:"type:varchar(500)" json:"summary"`                                              // 摘要
+	AuthorID   uint   `gorm:"index;index:idx_articles_author_published,priority:1;not null" json:"authorId"` // 作者ID
 	Author     *User  `gorm:"foreignKey:AuthorID" json:"author,omitempty"`
-	Status     int8   `gorm:"type:tinyint;default:0;index" json:"status"` // 0:草稿 1:已发布 2:已归档
+	Status     int8   `gorm:"type:tinyint;default:0;index" json:"status"` // 0:草稿 1:已发布 2:已归档 3:审核中 4:需修改 5:审核通过
 	Version    int    `gorm:"default:1" json:"version"`                   // 版本号
 	ViewCount  int64  `gorm:"default:0" json:"viewCount"`                 // 浏览量
 	LikeCount  int64  `gorm:"default:0" json:"likeCount"`                 // 点赞数
+
+	SummaryGenerated bool `gorm:"default:false" json:"summaryGenerated"` // 摘要是否由正文自动生成，手动填写后为 false
+	EditorID         uint `gorm:"default:0" json:"editorId"`             // 当前内容的最后编辑者，作者或协作者
+	RestoredFrom     int  `gorm:"default:0" json:"restoredFrom"`         // 当前内容恢复自哪个版本，0 表示普通编辑
+
+	PublishAt   *time.Time `gorm:"index" json:"publishAt"`                                                  // 定时发布时间
+	UnpublishAt *time.Time `gorm:"index" json:"unpublishAt"`                                                // 定时下线时间
+	PublishedAt *time.Time `gorm:"index;index:idx_articles_author_published,priority:2" json:"publishedAt"` // 首次发布时间
+	RestoredAt  *time.Time `json:"restoredAt"`                                                              // 最近一次从回收站恢复的时间
+
+	Tags       []Tag      `gorm:"many2many:article_tags" json:"tags,omitempty"`
+	Categories []Category `gorm:"many2many:article_categories" json:"categories,omitempty"`
 }
 
 func (Article) TableName() string {
@@ -28,6 +43,17 @@ type ArticleVersion struct {
 	Content   string `gorm:"type:longtext" json:"content"`
 	Version   int    `gorm:"not null" json:"version"`
 	Remark    string `gorm:"type:varchar(255)" json:"remark"` // 版本备注
+
+	Cover            string `gorm:"type:varchar(500)" json:"cover"`
+	Summary          string `gorm:"type:varchar(500)" json:"summary"`
+	SummaryGenerated bool   `gorm:"default:false" json:"summaryGenerated"`
+	Status           *int8  `gorm:"type:tinyint" json:"status"`    // 早期版本未记录，为空
+	RestoredFrom     int    `gorm:"default:0" json:"restoredFrom"` // 该版本内容恢复自哪个版本，0 表示普通编辑
+	EditorID         uint   `gorm:"default:0" json:"editorId"`     // 产生该版本内容的用户，早期版本未记录，为 0
+
+	// 每隔若干个版本存一次完整快照，其余版本只存相对上一个版本的压缩增量，Content 为空
+	Kind  int8   `gorm:"type:tinyint;not null;default:0" json:"kind"`
+	Delta []byte `json:"-"`
 }
 
 func (ArticleVersion) TableName() string {
@@ -35,10 +61,12 @@ func (ArticleVersion) TableName() string {
 }
 
 // ArticleDraft 文章草稿（实时保存）
+// 每个用户对每篇文章只有一份草稿；新文章（ArticleID 为 0）可以有多份，以名称区分
 type ArticleDraft struct {
 	BaseModel
-	ArticleID uint   `gorm:"uniqueIndex;not null" json:"articleId"` // 0 表示新文章
-	UserID    uint   `gorm:"index;not null" json:"userId"`
+	UserID    uint   `gorm:"uniqueIndex:idx_article_drafts_owner,priority:1;not null" json:"userId"`
+	ArticleID uint   `gorm:"uniqueIndex:idx_article_drafts_owner,priority:2;not null" json:"articleId"`     // 0 表示新文章
+	Name      string `gorm:"uniqueIndex:idx_article_drafts_owner,priority:3;type:varchar(100)" json:"name"` // 新文章草稿的名称
 	Title     string `gorm:"type:varchar(255)" json:"title"`
 	Content   string `gorm:"type:longtext" json:"content"`
 }
@@ -47,9 +75,61 @@ func (ArticleDraft) TableName() string {
 	return "article_drafts"
 }
 
+// DraftSnapshot 草稿自动保存的历史快照，每份草稿保留最近若干个
+type DraftSnapshot struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	DraftID   uint      `gorm:"index;not null" json:"draftId"`
+	Title     string    `gorm:"type:varchar(255)" json:"title"`
+	Content   string    `gorm:"type:longtext" json:"content"`
+	Size      int       `gorm:"not null" json:"size"`        // 内容字符数
+	Pinned    bool      `gorm:"default:false" json:"pinned"` // 内容骤减前的快照，与普通快照分开轮换
+	CreatedAt time.Time `gorm:"index" json:"createdAt"`
+}
+
+func (DraftSnapshot) TableName() string {
+	return "draft_snapshots"
+}
+
+// ArticleSlug 文章永久链接（含历史），旧链接保留用于跳转
+type ArticleSlug struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	ArticleID uint      `gorm:"index;not null" json:"articleId"`
+	Slug      string    `gorm:"type:varchar(200);uniqueIndex;not null" json:"slug"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (ArticleSlug) TableName() string {
+	return "article_slugs"
+}
+
 // ArticleStatus 文章状态常量
 const (
-	ArticleStatusDraft     int8 = 0 // 草稿
-	ArticleStatusPublished int8 = 1 // 已发布
-	ArticleStatusArchived  int8 = 2 // 已归档
+	ArticleStatusDraft            int8 = 0 // 草稿
+	ArticleStatusPublished        int8 = 1 // 已发布
+	ArticleStatusArchived         int8 = 2 // 已归档
+	ArticleStatusInReview         int8 = 3 // 审核中
+	ArticleStatusChangesRequested int8 = 4 // 需修改
+	ArticleStatusApproved         int8 = 5 // 审核通过，待发布
+)
+
+// ArticleVersion 存储方式
+const (
+	ArticleVersionSnapshot int8 = 0 // 完整快照
+	ArticleVersionDelta    int8 = 1 // 增量
 )
+
+// ArticleTransition 文章状态流转记录
+type ArticleTransition struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	ArticleID  uint      `gorm:"index;not null" json:"articleId"`
+	FromStatus int8      `gorm:"type:tinyint;not null" json:"fromStatus"`
+	ToStatus   int8      `gorm:"type:tinyint;not null" json:"toStatus"`
+	ActorID    uint      `gorm:"index" json:"actorId"` // 0 表示系统（如定时发布）
+	Actor      *User     `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+	Comment    string    `gorm:"type:varchar(1000)" json:"comment"`
+	CreatedAt  time.Time `gorm:"index" json:"createdAt"`
+}
+
+func (ArticleTransition) TableName() string {
+	return "article_transitions"
+}
@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// ArticleCollaborator 文章协作者。邀请后对方接受才生效，AcceptedAt 为空表示邀请待接受
+type ArticleCollaborator struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	ArticleID  uint       `gorm:"uniqueIndex:idx_article_collaborators_user,priority:1;not null" json:"articleId"`
+	UserID     uint       `gorm:"uniqueIndex:idx_article_collaborators_user,priority:2;index;not null" json:"userId"`
+	Role       string     `gorm:"type:varchar(20);not null" json:"role"`
+	InvitedBy  uint       `gorm:"not null" json:"invitedBy"`
+	AcceptedAt *time.Time `json:"acceptedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+func (ArticleCollaborator) TableName() string {
+	return "article_collaborators"
+}
+
+// 协作者角色
+const (
+	CollaboratorOwner    = "owner"     // 文章作者本人，不存储在协作者表中
+	CollaboratorCoAuthor = "co-author" // 共同作者：署名，可以编辑、提交审核和发布，可以管理协作者
+	CollaboratorEditor   = "editor"    // 编辑：可以修改内容，不能变更状态
+	CollaboratorViewer   = "viewer"    // 只读：可以查看协作者名单和未发布的系列文章，不能修改
+)
@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+// Comment 文章评论，回复统一挂在所在楼层（根评论）下
+type Comment struct {
+	BaseModel
+	ArticleID uint       `gorm:"index:idx_comments_article_root,priority:1;not null" json:"articleId"`
+	RootID    uint       `gorm:"index:idx_comments_article_root,priority:2;default:0;comment:所在楼层的根评论ID，根评论为0" json:"rootId"`
+	ParentID  uint       `gorm:"default:0;comment:回复的评论ID，根评论为0" json:"parentId"`
+	UserID    uint       `gorm:"index;not null" json:"userId"`
+	Body      string     `gorm:"type:text" json:"body"`     // Markdown 原文
+	BodyHTML  string     `gorm:"type:text" json:"bodyHtml"` // 渲染并过滤后的 HTML
+	Status    int8       `gorm:"type:tinyint;default:0;comment:状态 0:待审核 1:已通过 2:垃圾 3:已隐藏" json:"status"`
+	Pinned    bool       `gorm:"default:false" json:"pinned"`
+	Removed   bool       `gorm:"default:false;comment:已删除但保留楼层" json:"removed"`
+	EditedAt  *time.Time `json:"editedAt"`
+
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+func (Comment) TableName() string {
+	return "comments"
+}
+
+// CommentStatus 评论状态常量
+const (
+	CommentStatusPending  int8 = 0 // 待审核
+	CommentStatusApproved int8 = 1 // 已通过
+	CommentStatusSpam     int8 = 2 // 垃圾评论
+	CommentStatusHidden   int8 = 3 // 已隐藏
+)
+
+// Notification 站内通知
+type Notification struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	UserID    uint      `gorm:"uniqueIndex:idx_notifications_target,priority:1;index:idx_notifications_user_read,priority:1;not null" json:"userId"`
+	Type      string    `gorm:"uniqueIndex:idx_notifications_target,priority:2;type:varchar(32);not null" json:"type"`
+	CommentID uint      `gorm:"uniqueIndex:idx_notifications_target,priority:3;default:0" json:"commentId"`
+	ActorID   uint      `gorm:"not null" json:"actorId"`
+	ArticleID uint      `gorm:"not null" json:"articleId"`
+	IsRead    bool      `gorm:"index:idx_notifications_user_read,priority:2;default:false" json:"read"`
+	CreatedAt time.Time `gorm:"index" json:"createdAt"`
+
+	Actor *User `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+}
+
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// NotificationType 通知类型
+const (
+	NotificationComment = "comment" // 文章收到评论
+	NotificationReply   = "reply"   // 评论收到回复
+)
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// Follow 用户之间的关注关系
+type Follow struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	FollowerID uint      `gorm:"uniqueIndex:idx_follows_pair,priority:1;not null" json:"followerId"` // 关注者
+	FolloweeID uint      `gorm:"uniqueIndex:idx_follows_pair,priority:2;index;not null" json:"followeeId"`
+	CreatedAt  time.Time `gorm:"index" json:"createdAt"`
+}
+
+func (Follow) TableName() string {
+	return "follows"
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// JobLease 后台任务租约，多副本部署时保证同一任务同一时间只在一个实例上执行
+type JobLease struct {
+	Name      string    `gorm:"type:varchar(100);primaryKey" json:"name"`
+	Owner     string    `gorm:"type:varchar(100);not null" json:"owner"`
+	ExpiresAt time.Time `gorm:"not null" json:"expiresAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (JobLease) TableName() string {
+	return "job_leases"
+}
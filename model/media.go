@@ -0,0 +1,80 @@
+package model
+
+import "time"
+
+// Media 上传的文件。相同内容只在存储中保存一份（按 SHA-256 生成存储 key），
+// 同一用户重复上传时返回已有记录。图片保存前会去掉 EXIF 等元数据
+type Media struct {
+	ID          uint   `gorm:"primarykey" json:"id"`
+	OwnerID     uint   `gorm:"uniqueIndex:idx_media_owner_hash,priority:1;not null" json:"ownerId"`
+	Hash        string `gorm:"uniqueIndex:idx_media_owner_hash,priority:2;index;type:char(64);not null" json:"hash"` // 上传内容的 SHA-256
+	StorageKey  string `gorm:"type:varchar(255);not null" json:"-"`
+	URL         string `gorm:"type:varchar(500);index;not null" json:"url"`
+	Filename    string `gorm:"type:varchar(255)" json:"filename"` // 上传时的原始文件名
+	ContentType string `gorm:"type:varchar(100);not null" json:"contentType"`
+	Size        int64  `gorm:"not null" json:"size"`
+	Width       int    `gorm:"default:0" json:"width"` // 图片尺寸，无法识别时为 0
+	Height      int    `gorm:"default:0" json:"height"`
+
+	VariantStatus string `gorm:"type:varchar(20);index;default:''" json:"variantStatus"` // 图片变体的处理状态，非图片为空
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (Media) TableName() string {
+	return "media"
+}
+
+// 图片变体的处理状态
+const (
+	MediaVariantPending    = "pending"
+	MediaVariantProcessing = "processing"
+	MediaVariantReady      = "ready"
+	MediaVariantFailed     = "failed"
+)
+
+// 图片变体名称，按宽度从小到大
+const (
+	MediaVariantThumbnail = "thumbnail"
+	MediaVariantMedium    = "medium"
+	MediaVariantLarge     = "large"
+)
+
+// MediaVariant 图片按宽度缩放后的版本，存储 key 由原图的 key 加上变体名称生成，
+// 相同内容的多条记录共用同一组对象
+type MediaVariant struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	MediaID     uint      `gorm:"uniqueIndex:idx_media_variants_name,priority:1;not null" json:"mediaId"`
+	Name        string    `gorm:"uniqueIndex:idx_media_variants_name,priority:2;type:varchar(20);not null" json:"name"`
+	StorageKey  string    `gorm:"type:varchar(255);not null" json:"-"`
+	URL         string    `gorm:"type:varchar(500);not null" json:"url"`
+	ContentType string    `gorm:"type:varchar(100);not null" json:"contentType"`
+	Size        int64     `gorm:"not null" json:"size"`
+	Width       int       `gorm:"not null" json:"width"`
+	Height      int       `gorm:"not null" json:"height"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+func (MediaVariant) TableName() string {
+	return "media_variants"
+}
+
+// 文章引用文件的位置
+const (
+	MediaRefCover   = "cover"
+	MediaRefContent = "content"
+)
+
+// MediaReference 文章对文件的引用，保存文章时根据封面和正文中的图片同步
+type MediaReference struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	MediaID   uint      `gorm:"uniqueIndex:idx_media_refs_article,priority:1;not null" json:"mediaId"`
+	ArticleID uint      `gorm:"uniqueIndex:idx_media_refs_article,priority:2;index;not null" json:"articleId"`
+	Field     string    `gorm:"uniqueIndex:idx_media_refs_article,priority:3;type:varchar(20);not null" json:"field"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (MediaReference) TableName() string {
+	return "media_references"
+}
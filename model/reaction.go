@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// ArticleLike 点赞记录，每个用户对每篇文章只能点赞一次
+type ArticleLike struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	ArticleID uint      `gorm:"uniqueIndex:idx_article_likes_user,priority:1;not null" json:"articleId"`
+	UserID    uint      `gorm:"uniqueIndex:idx_article_likes_user,priority:2;index;not null" json:"userId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (ArticleLike) TableName() string {
+	return "article_likes"
+}
+
+// ArticleReaction 表情回应，每个用户对每篇文章的每种表情只能回应一次
+type ArticleReaction struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	ArticleID uint      `gorm:"uniqueIndex:idx_article_reactions_user,priority:1;not null" json:"articleId"`
+	UserID    uint      `gorm:"uniqueIndex:idx_article_reactions_user,priority:2;not null" json:"userId"`
+	Emoji     string    `gorm:"uniqueIndex:idx_article_reactions_user,priority:3;type:varchar(32);not null" json:"emoji"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (ArticleReaction) TableName() string {
+	return "article_reactions"
+}
+
+// Bookmark 收藏，仅本人可见
+type Bookmark struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	UserID    uint      `gorm:"uniqueIndex:idx_bookmarks_user,priority:1;not null" json:"userId"`
+	ArticleID uint      `gorm:"uniqueIndex:idx_bookmarks_user,priority:2;index;not null" json:"articleId"`
+	CreatedAt time.Time `gorm:"index" json:"createdAt"`
+}
+
+func (Bookmark) TableName() string {
+	return "bookmarks"
+}
+
+// ReactionEmojis 支持的表情回应
+var ReactionEmojis = []string{"thumbsup", "heart", "laugh", "hooray", "confused", "rocket", "eyes"}
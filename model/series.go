@@ -0,0 +1,26 @@
+package model
+
+// Series 文章系列（有序合集）
+type Series struct {
+	BaseModel
+	Title       string `gorm:"type:varchar(255);not null" json:"title"`
+	Description string `gorm:"type:varchar(1000)" json:"description"`
+	AuthorID    uint   `gorm:"index;not null" json:"authorId"`
+	Author      *User  `gorm:"foreignKey:AuthorID" json:"author,omitempty"`
+}
+
+func (Series) TableName() string {
+	return "series"
+}
+
+// SeriesArticle 系列中的文章及其顺序，一篇文章只能属于一个系列
+type SeriesArticle struct {
+	ID        uint `gorm:"primarykey" json:"id"`
+	SeriesID  uint `gorm:"index:idx_series_position;not null" json:"seriesId"`
+	ArticleID uint `gorm:"uniqueIndex;not null" json:"articleId"`
+	Position  int  `gorm:"index:idx_series_position;not null" json:"position"` // 从 1 开始
+}
+
+func (SeriesArticle) TableName() string {
+	return "series_articles"
+}
@@ -0,0 +1,43 @@
+package model
+
+// Tag 文章标签
+type Tag struct {
+	BaseModel
+	Name string `gorm:"type:varchar(50);uniqueIndex;not null" json:"name"`
+}
+
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// Category 文章分类
+type Category struct {
+	BaseModel
+	Name        string `gorm:"type:varchar(50);uniqueIndex;not null" json:"name"`
+	Description string `gorm:"type:varchar(255)" json:"description"`
+	Sort        int    `gorm:"default:0" json:"sort"` // 排序，越小越靠前
+}
+
+func (Category) TableName() string {
+	return "categories"
+}
+
+// ArticleTag 文章-标签关联
+type ArticleTag struct {
+	ArticleID uint `gorm:"primaryKey" json:"articleId"`
+	TagID     uint `gorm:"primaryKey;index" json:"tagId"`
+}
+
+func (ArticleTag) TableName() string {
+	return "article_tags"
+}
+
+// ArticleCategory 文章-分类关联
+type ArticleCategory struct {
+	ArticleID  uint `gorm:"primaryKey" json:"articleId"`
+	CategoryID uint `gorm:"primaryKey;index" json:"categoryId"`
+}
+
+func (ArticleCategory) TableName() string {
+	return "article_categories"
+}
@@ -10,8 +10,19 @@ type User struct {
 	Avatar   string `gorm:"type:varchar(255)" json:"avatar"`
 	Phone    string `gorm:"type:varchar(20);index" json:"phone"`
 	Status   int8   `gorm:"type:tinyint;default:1;comment:状态 1:正常 0:禁用" json:"status"`
+	Role     int8   `gorm:"type:tinyint;default:0;comment:角色 0:普通用户 1:管理员 2:审核员" json:"role"`
+
+	FollowerCount  int64 `gorm:"default:0" json:"followerCount"`  // 粉丝数
+	FollowingCount int64 `gorm:"default:0" json:"followingCount"` // 关注数
 }
 
+// UserRole 用户角色常量
+const (
+	UserRoleNormal   int8 = 0 // 普通用户
+	UserRoleAdmin    int8 = 1 // 管理员
+	UserRoleReviewer int8 = 2 // 审核员
+)
+
 // TableName 表名
 func (User) TableName() string {
 	return "users"
@@ -21,3 +32,13 @@ func (User) TableName() string {
 func (u *User) IsActive() bool {
 	return u.Status == 1
 }
+
+// IsAdmin 判断用户是否为管理员
+func (u *User) IsAdmin() bool {
+	return u.Role == UserRoleAdmin
+}
+
+// IsReviewer 判断用户是否可以审核文章（管理员也可以审核）
+func (u *User) IsReviewer() bool {
+	return u.Role == UserRoleReviewer || u.Role == UserRoleAdmin
+}